@@ -11,10 +11,14 @@ type Visitor interface {
 	VisitAssignExpr(assign *Assign) (any, error)
 	VisitBinaryExpr(binary *Binary) (any, error)
 	VisitCallExpr(call *Call) (any, error)
-	// VisitGetExpr(get *Get) (any, error)
+	VisitCoalesceExpr(coalesce *Coalesce) (any, error)
+	VisitConditionalExpr(conditional *Conditional) (any, error)
+	VisitGetExpr(get *Get) (any, error)
 	VisitGroupingExpr(grouping *Grouping) (any, error)
 	VisitLiteralExpr(literal *Literal) (any, error)
 	VisitLogicalExpr(logical *Logical) (any, error)
+	VisitPostfixExpr(postfix *Postfix) (any, error)
+	VisitSpawnExpr(spawn *Spawn) (any, error)
 	// VisitSetExpr(set *Set) (any, error)
 	// VisitSuperExpr(super *Super) (any, error)
 	// VisitThisExpr(this *This) (any, error)
@@ -53,26 +57,59 @@ func (b *Binary) Accept(visitor Visitor) (any, error) {
 	return visitor.VisitBinaryExpr(b)
 }
 
-// Call represents a function call.
+// Call represents a function call. Named
+// arguments follow the positional ones.
 type Call struct {
 	Callee    Expr
 	Paren     token.Token
 	Arguments []Expr
+	Named     []NamedArgument
+}
+
+// NamedArgument is an argument passed
+// with the name of its parameter.
+type NamedArgument struct {
+	Name  token.Token
+	Value Expr
 }
 
 func (c *Call) Accept(visitor Visitor) (any, error) {
 	return visitor.VisitCallExpr(c)
 }
 
+// Coalesce represents a null-coalescing
+// expression (a ?? b).
+type Coalesce struct {
+	Left     Expr
+	Right    Expr
+	Operator token.Token
+}
+
+func (c *Coalesce) Accept(visitor Visitor) (any, error) {
+	return visitor.VisitCoalesceExpr(c)
+}
+
+// Conditional represents a conditional
+// expression (cond ? a : b).
+type Conditional struct {
+	Condition  Expr
+	ThenBranch Expr
+	ElseBranch Expr
+}
+
+func (c *Conditional) Accept(visitor Visitor) (any, error) {
+	return visitor.VisitConditionalExpr(c)
+}
+
 // Get represents getting an object's property.
 type Get struct {
 	Object Expr
 	Name   token.Token
 }
 
-// func (g *Get) Accept(visitor Visitor) (any, error) {
-// 	return visitor.VisitGetExpr(g)
-// }
+func (g *Get) Accept(visitor Visitor) (any, error) {
+	return visitor.VisitGetExpr(g)
+}
 
 // Group represents grouping of expression
 // with parentheses.
@@ -104,6 +141,28 @@ func (l *Logical) Accept(visitor Visitor) (any, error) {
 	return visitor.VisitLogicalExpr(l)
 }
 
+// Postfix represents a postfix increment
+// or decrement of a variable.
+type Postfix struct {
+	Name     token.Token
+	Operator token.Token
+}
+
+func (p *Postfix) Accept(visitor Visitor) (any, error) {
+	return visitor.VisitPostfixExpr(p)
+}
+
+// Spawn represents a function call
+// running on its own task.
+type Spawn struct {
+	Keyword token.Token
+	Call    *Call
+}
+
+func (s *Spawn) Accept(visitor Visitor) (any, error) {
+	return visitor.VisitSpawnExpr(s)
+}
+
 // Set sets an object's property to a value.
 type Set struct {
 	Object Expr
@@ -0,0 +1,147 @@
+package interpreter
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Array is a golox array, a growable list of values.
+type Array struct {
+	Elements []any
+}
+
+func (a *Array) String() string {
+	s, err := repr(a, make(map[any]bool), 0)
+	if err != nil {
+		return "[...]"
+	}
+
+	return s
+}
+
+// Map is a golox map from strings to values.
+// Keys are kept in insertion order.
+type Map struct {
+	keys   []string
+	values map[string]any
+}
+
+// NewMap creates an empty Map.
+func NewMap() *Map {
+	return &Map{
+		values: make(map[string]any),
+	}
+}
+
+// Get returns the value of a key.
+func (m *Map) Get(key string) (any, bool) {
+	v, ok := m.values[key]
+	return v, ok
+}
+
+// Set sets the value of a key, adding the
+// key after the others if it is new.
+func (m *Map) Set(key string, value any) {
+	if _, ok := m.values[key]; !ok {
+		m.keys = append(m.keys, key)
+	}
+
+	m.values[key] = value
+}
+
+// Keys returns the keys in insertion order.
+func (m *Map) Keys() []string {
+	return m.keys
+}
+
+func (m *Map) String() string {
+	s, err := repr(m, make(map[any]bool), 0)
+	if err != nil {
+		return "{...}"
+	}
+
+	return s
+}
+
+// maxDepth is the deepest nesting of arrays and maps that
+// can be printed or converted to JSON, to keep deeply nested
+// values from overflowing the stack. It is the nesting
+// limit of jsonParse.
+const maxDepth = 10000
+
+// errDepth is returned for values nested deeper than maxDepth.
+var errDepth = errors.New("value nested too deeply")
+
+// toString returns the representation of a value
+// printed by a script.
+func toString(value any) (string, error) {
+	switch value.(type) {
+	case *Array, *Map:
+		return repr(value, make(map[any]bool), 0)
+	}
+
+	return fmt.Sprint(value), nil
+}
+
+// repr returns the representation of a value inside
+// a container, where strings are quoted. seen contains
+// the containers being printed, which are printed as
+// "[...]" or "{...}" when they contain themselves.
+// depth is the number of containers around value.
+func repr(value any, seen map[any]bool, depth int) (string, error) {
+	switch v := value.(type) {
+	case nil:
+		return "nil", nil
+	case string:
+		return fmt.Sprintf("%q", v), nil
+	case *Array:
+		if seen[v] {
+			return "[...]", nil
+		}
+
+		if depth >= maxDepth {
+			return "", errDepth
+		}
+
+		seen[v] = true
+		defer delete(seen, v)
+
+		var elements []string
+		for _, element := range v.Elements {
+			s, err := repr(element, seen, depth+1)
+			if err != nil {
+				return "", err
+			}
+
+			elements = append(elements, s)
+		}
+
+		return "[" + strings.Join(elements, ", ") + "]", nil
+	case *Map:
+		if seen[v] {
+			return "{...}", nil
+		}
+
+		if depth >= maxDepth {
+			return "", errDepth
+		}
+
+		seen[v] = true
+		defer delete(seen, v)
+
+		var entries []string
+		for _, key := range v.keys {
+			s, err := repr(v.values[key], seen, depth+1)
+			if err != nil {
+				return "", err
+			}
+
+			entries = append(entries, fmt.Sprintf("%q: %v", key, s))
+		}
+
+		return "{" + strings.Join(entries, ", ") + "}", nil
+	}
+
+	return fmt.Sprint(value), nil
+}
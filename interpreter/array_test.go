@@ -0,0 +1,65 @@
+package interpreter
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+)
+
+// nested returns a script declaring a, an array
+// containing arrays nested depth times.
+func nested(depth int) string {
+	return fmt.Sprintf("var a = array(); for (var i = 1; i < %v; i++) a = array(a);", depth)
+}
+
+func TestRepr(t *testing.T) {
+	tests := []struct {
+		source        string
+		expected      string
+		expectedError string
+	}{
+		{`print array(1, "a", nil, array(true));`, "[1, \"a\", nil, [true]]\n", ""},
+		{`var m = map(); set(m, "k", array(1)); print m;`, "{\"k\": [1]}\n", ""},
+		{`var a = array(); push(a, a); print a;`, "[[...]]\n", ""},
+		{`var m = map(); set(m, "m", m); print m;`, "{\"m\": {...}}\n", ""},
+		{`var a = array(); print array(a, a);`, "[[], []]\n", ""},
+		{nested(3) + "print a;", "[[[]]]\n", ""},
+		{nested(maxDepth) + "print len(format(\"{}\", a));", fmt.Sprintf("%v\n", 2*maxDepth), ""},
+
+		// values nested too deeply are errors
+		// rather than overflowing the stack.
+		{nested(maxDepth+1) + "print a;", "", "Cannot print value: value nested too deeply."},
+		{nested(maxDepth+1) + "write(a);", "", "write: value nested too deeply."},
+		{nested(maxDepth+1) + "format(\"{}\", a);", "", "format: placeholder {}: value nested too deeply."},
+	}
+
+	for i, tt := range tests {
+		var output bytes.Buffer
+
+		interpreter := New()
+		interpreter.Stdout = &output
+
+		err := interpret(t, interpreter, tt.source)
+		if tt.expectedError == "" && err != nil || tt.expectedError != "" && (err == nil || err.Error() != tt.expectedError) {
+			t.Fatalf("tests[%d] - error wrong. expected=%q, got=%v", i, tt.expectedError, err)
+		}
+
+		if output.String() != tt.expected {
+			t.Fatalf("tests[%d] - output wrong. expected=%q, got=%q", i, tt.expected, output.String())
+		}
+	}
+
+	if s := fmt.Sprint(deepArray(maxDepth + 1)); s != "[...]" {
+		t.Fatalf("String wrong for an array nested too deeply. got=%q", s)
+	}
+}
+
+// deepArray returns arrays nested depth times.
+func deepArray(depth int) *Array {
+	a := &Array{}
+	for n := 1; n < depth; n++ {
+		a = &Array{Elements: []any{a}}
+	}
+
+	return a
+}
@@ -0,0 +1,87 @@
+package interpreter
+
+import (
+	"fmt"
+	"golox/parser"
+	"golox/scanner"
+	"golox/token"
+	"testing"
+)
+
+func TestClosures(t *testing.T) {
+	tests := []struct {
+		source   string
+		expected string
+	}{
+		{`
+		fun makeCounter() {
+			var count = 0;
+			fun increment() {
+				count++;
+				return count;
+			}
+			return increment;
+		}
+		var counter = makeCounter();
+		counter();
+		var result = counter();
+		`, "2"},
+		{`
+		var x = "global";
+		fun outer() {
+			var x = "local";
+			fun inner() {
+				return x;
+			}
+			return inner();
+		}
+		var result = outer();
+		`, "local"},
+		{`
+		fun adder(n) {
+			fun add(m) {
+				return n + m;
+			}
+			return add;
+		}
+		var addTwo = adder(2);
+		var addTen = adder(10);
+		var result = addTwo(1) + addTen(1);
+		`, "14"},
+		{`
+		var x = 1;
+		fun get() {
+			return x;
+		}
+		x = 2;
+		var result = get();
+		`, "2"},
+	}
+
+	for i, tt := range tests {
+		scanner := scanner.New(tt.source)
+		parser := parser.Parser{
+			Tokens: scanner.ScanTokens(),
+		}
+
+		statements, isError := parser.Parse()
+		if isError {
+			t.Fatalf("tests[%d] - cannot parse %q", i, tt.source)
+		}
+
+		interpreter := New()
+		err := interpreter.Interpret(statements)
+		if err != nil {
+			t.Fatalf("tests[%d] - unexpected error: %v", i, err)
+		}
+
+		result, err := interpreter.Globals.Get(token.Token{Lexeme: "result"})
+		if err != nil {
+			t.Fatalf("tests[%d] - unexpected error: %v", i, err)
+		}
+
+		if fmt.Sprint(result) != tt.expected {
+			t.Fatalf("tests[%d] - result wrong. expected=%v, got=%v", i, tt.expected, result)
+		}
+	}
+}
@@ -0,0 +1,183 @@
+package interpreter
+
+import (
+	"errors"
+	"fmt"
+	"unicode/utf8"
+)
+
+// mapArgument returns the argument at index n,
+// checking that it is a map.
+func mapArgument(function string, arguments []any, n int) (*Map, error) {
+	m, ok := arguments[n].(*Map)
+	if !ok {
+		return nil, fmt.Errorf("%v: argument %v must be a map.", function, n+1)
+	}
+
+	return m, nil
+}
+
+// arrayIndex returns the index argument at index n,
+// checking that it is within the bounds of array.
+func arrayIndex(function string, array *Array, arguments []any, n int) (int, error) {
+	index, err := intArgument(function, arguments, n)
+	if err != nil {
+		return 0, err
+	}
+
+	if index < 0 || index >= len(array.Elements) {
+		return 0, fmt.Errorf("%v: index %v out of range.", function, index)
+	}
+
+	return index, nil
+}
+
+// collectionNatives returns the natives creating
+// and accessing arrays and maps.
+func collectionNatives() []*NativeFunction {
+	return []*NativeFunction{
+		{
+			Name:   "array",
+			Params: Variadic,
+			Function: func(interpreter *Interpreter, arguments []any) (any, error) {
+				return interpreter.allocate(&Array{
+					Elements: append([]any{}, arguments...),
+				})
+			},
+		},
+		{
+			Name:   "map",
+			Params: 0,
+			Function: func(interpreter *Interpreter, arguments []any) (any, error) {
+				return NewMap(), nil
+			},
+		},
+		{
+			Name:   "len",
+			Params: 1,
+			Function: func(interpreter *Interpreter, arguments []any) (any, error) {
+				switch v := arguments[0].(type) {
+				case string:
+					return int64(utf8.RuneCountInString(v)), nil
+				case *Array:
+					return int64(len(v.Elements)), nil
+				case *Map:
+					return int64(len(v.keys)), nil
+				}
+
+				return nil, errors.New("len: argument 1 must be a string, an array or a map.")
+			},
+		},
+		{
+			Name:   "get",
+			Params: 2,
+			Function: func(interpreter *Interpreter, arguments []any) (any, error) {
+				switch container := arguments[0].(type) {
+				case *Array:
+					index, err := arrayIndex("get", container, arguments, 1)
+					if err != nil {
+						return nil, err
+					}
+
+					return container.Elements[index], nil
+				case *Map:
+					key, err := stringArgument("get", arguments, 1)
+					if err != nil {
+						return nil, err
+					}
+
+					value, _ := container.Get(key)
+					return value, nil
+				}
+
+				return nil, errors.New("get: argument 1 must be an array or a map.")
+			},
+		},
+		{
+			Name:   "set",
+			Params: 3,
+			Function: func(interpreter *Interpreter, arguments []any) (any, error) {
+				switch container := arguments[0].(type) {
+				case *Array:
+					index, err := arrayIndex("set", container, arguments, 1)
+					if err != nil {
+						return nil, err
+					}
+
+					container.Elements[index] = arguments[2]
+					return nil, nil
+				case *Map:
+					key, err := stringArgument("set", arguments, 1)
+					if err != nil {
+						return nil, err
+					}
+
+					if _, ok := container.Get(key); !ok {
+						err = interpreter.charge(entrySize + uint64(len(key)))
+						if err != nil {
+							return nil, err
+						}
+					}
+
+					container.Set(key, arguments[2])
+					return nil, nil
+				}
+
+				return nil, errors.New("set: argument 1 must be an array or a map.")
+			},
+		},
+		{
+			Name:   "has",
+			Params: 2,
+			Function: func(interpreter *Interpreter, arguments []any) (any, error) {
+				m, err := mapArgument("has", arguments, 0)
+				if err != nil {
+					return nil, err
+				}
+
+				key, err := stringArgument("has", arguments, 1)
+				if err != nil {
+					return nil, err
+				}
+
+				_, ok := m.Get(key)
+				return ok, nil
+			},
+		},
+		{
+			Name:   "keys",
+			Params: 1,
+			Function: func(interpreter *Interpreter, arguments []any) (any, error) {
+				m, err := mapArgument("keys", arguments, 0)
+				if err != nil {
+					return nil, err
+				}
+
+				keys := &Array{}
+				for _, key := range m.Keys() {
+					keys.Elements = append(keys.Elements, key)
+				}
+
+				return interpreter.allocate(keys)
+			},
+		},
+		{
+			Name:   "push",
+			Params: 2,
+			Function: func(interpreter *Interpreter, arguments []any) (any, error) {
+				array, err := arrayArgument("push", arguments, 0)
+				if err != nil {
+					return nil, err
+				}
+
+				err = interpreter.charge(elementSize)
+				if err != nil {
+					return nil, err
+				}
+
+				array.Elements = append(array.Elements, arguments[1])
+				return nil, nil
+			},
+		},
+	}
+}
@@ -14,12 +14,18 @@ type Environment struct {
 	// environment. Beware that the map is nil
 	// and needs to be initialized before using.
 	Values map[string]any
+
+	// Constants contains the names of the variables
+	// that cannot be reassigned. Like Values, it needs
+	// to be initialized before using.
+	Constants map[string]bool
 }
 
 func NewEnvironment(enclosing Environment) Environment {
 	return Environment{
 		Enclosing: &enclosing,
 		Values:    make(map[string]any),
+		Constants: make(map[string]bool),
 	}
 }
 
@@ -27,6 +33,30 @@ func (e *Environment) Define(name string, value any) {
 	e.Values[name] = value
 }
 
+// DefineConstant defines a variable that
+// cannot be reassigned.
+func (e *Environment) DefineConstant(name string, value any) {
+	e.Values[name] = value
+	e.Constants[name] = true
+}
+
+// declare defines a variable declared in a golox
+// script. Constants cannot be redeclared in the
+// same scope.
+func (e *Environment) declare(name token.Token, value any, constant bool) error {
+	if e.Constants[name.Lexeme] {
+		return fmt.Errorf("Cannot redeclare constant %v.", name.Lexeme)
+	}
+
+	if constant {
+		e.DefineConstant(name.Lexeme, value)
+	} else {
+		e.Define(name.Lexeme, value)
+	}
+
+	return nil
+}
+
 func (e *Environment) Get(name token.Token) (any, error) {
 	if v, ok := e.Values[name.Lexeme]; ok {
 		return v, nil
@@ -41,13 +71,16 @@ func (e *Environment) Get(name token.Token) (any, error) {
 
 func (e *Environment) assign(name token.Token, value any) error {
 	if _, ok := e.Values[name.Lexeme]; ok {
+		if e.Constants[name.Lexeme] {
+			return fmt.Errorf("Cannot assign to constant %v.", name.Lexeme)
+		}
+
 		e.Values[name.Lexeme] = value
 		return nil
 	}
 
 	if e.Enclosing != nil {
-		e.Enclosing.assign(name, value)
-		return nil
+		return e.Enclosing.assign(name, value)
 	}
 
 	return fmt.Errorf("Undefined variable %v.", name.Lexeme)
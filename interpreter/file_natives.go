@@ -0,0 +1,259 @@
+package interpreter
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// FileAccess restricts the files that scripts can access.
+// An Interpreter without a FileAccess cannot access files.
+type FileAccess struct {
+	// Roots contains the directories that can be accessed,
+	// including their subdirectories. Any file can be
+	// accessed if Roots is empty.
+	Roots []string
+
+	// ReadOnly denies writing and removing files.
+	ReadOnly bool
+}
+
+// resolvePath makes a path absolute and resolves the symbolic
+// links in it. Missing files are allowed, in which case the
+// links are resolved in the closest existing parent.
+func resolvePath(path string) (string, error) {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return "", err
+	}
+
+	missing := ""
+	for {
+		resolved, err := filepath.EvalSymlinks(abs)
+		if err == nil {
+			return filepath.Join(resolved, missing), nil
+		}
+
+		parent := filepath.Dir(abs)
+		if parent == abs {
+			return "", err
+		}
+
+		missing = filepath.Join(filepath.Base(abs), missing)
+		abs = parent
+	}
+}
+
+// isWithin checks if path is dir or is inside dir.
+func isWithin(path string, dir string) bool {
+	rel, err := filepath.Rel(dir, path)
+	if err != nil {
+		return false
+	}
+
+	return rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
+
+// checkFileAccess checks if a native may access path, returning
+// the path to use. Relative paths are relative to the directory
+// of the script, like imports. write is set for natives that
+// modify the file system.
+func (i *Interpreter) checkFileAccess(function string, path string, write bool) (string, error) {
+	if i.Files == nil {
+		return "", fmt.Errorf("%v: file access is disabled.", function)
+	}
+
+	if write && i.Files.ReadOnly {
+		return "", fmt.Errorf("%v: file access is read-only.", function)
+	}
+
+	abs := path
+	if !filepath.IsAbs(abs) {
+		abs = filepath.Join(i.Dir, abs)
+	}
+
+	resolved, err := resolvePath(abs)
+	if err != nil {
+		return "", fmt.Errorf("%v: %v", function, err)
+	}
+
+	if len(i.Files.Roots) == 0 {
+		return resolved, nil
+	}
+
+	for _, root := range i.Files.Roots {
+		resolvedRoot, err := resolvePath(root)
+		if err == nil && isWithin(resolved, resolvedRoot) {
+			return resolved, nil
+		}
+	}
+
+	return "", fmt.Errorf("%v: access to %v is denied.", function, path)
+}
+
+// File is a file opened by a script for reading.
+type File struct {
+	Path string
+
+	file   *os.File
+	reader *bufio.Reader
+}
+
+func (f *File) String() string {
+	return fmt.Sprintf("<file %v>", f.Path)
+}
+
+// readLine reads the next line from the file without
+// its line ending, returning nil at the end of the file.
+func (f *File) readLine() (any, error) {
+	if f.file == nil {
+		return nil, errors.New("readLine: file is closed.")
+	}
+
+	line, err := readLine(f.reader)
+	if err != nil {
+		return nil, fmt.Errorf("readLine: %v", err)
+	}
+
+	return line, nil
+}
+
+// pathNative creates a file native taking a path
+// and possibly other string arguments.
+func pathNative(
+	name string,
+	params int,
+	write bool,
+	f func(path string, arguments []string) (any, error),
+) *NativeFunction {
+	return &NativeFunction{
+		Name:   name,
+		Params: params,
+		Function: func(interpreter *Interpreter, arguments []any) (any, error) {
+			var strs []string
+			for n := range arguments {
+				s, err := stringArgument(name, arguments, n)
+				if err != nil {
+					return nil, err
+				}
+
+				strs = append(strs, s)
+			}
+
+			path, err := interpreter.checkFileAccess(name, strs[0], write)
+			if err != nil {
+				return nil, err
+			}
+
+			value, err := f(path, strs[1:])
+			if err != nil {
+				return nil, fmt.Errorf("%v: %v", name, err)
+			}
+
+			return interpreter.allocate(value)
+		},
+	}
+}
+
+// fileNatives returns the natives accessing files.
+func fileNatives() []*NativeFunction {
+	return []*NativeFunction{
+		pathNative("readFile", 1, false, func(path string, arguments []string) (any, error) {
+			data, err := os.ReadFile(path)
+			if err != nil {
+				return nil, err
+			}
+
+			return string(data), nil
+		}),
+		pathNative("writeFile", 2, true, func(path string, arguments []string) (any, error) {
+			return nil, os.WriteFile(path, []byte(arguments[0]), 0644)
+		}),
+		pathNative("appendFile", 2, true, func(path string, arguments []string) (any, error) {
+			file, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+			if err != nil {
+				return nil, err
+			}
+
+			_, err = file.WriteString(arguments[0])
+			if err != nil {
+				file.Close()
+				return nil, err
+			}
+
+			return nil, file.Close()
+		}),
+		pathNative("exists", 1, false, func(path string, arguments []string) (any, error) {
+			_, err := os.Stat(path)
+			return err == nil, nil
+		}),
+		pathNative("remove", 1, true, func(path string, arguments []string) (any, error) {
+			return nil, os.Remove(path)
+		}),
+		pathNative("open", 1, false, func(path string, arguments []string) (any, error) {
+			file, err := os.Open(path)
+			if err != nil {
+				return nil, err
+			}
+
+			return &File{
+				Path:   path,
+				file:   file,
+				reader: bufio.NewReader(file),
+			}, nil
+		}),
+		{
+			Name:   "readLine",
+			Params: Variadic,
+			Function: func(interpreter *Interpreter, arguments []any) (any, error) {
+				err := checkArgumentCount("readLine", arguments, 0, 1)
+				if err != nil {
+					return nil, err
+				}
+
+				// without a file, read from the standard input.
+				if len(arguments) == 0 {
+					return interpreter.readStdin("readLine")
+				}
+
+				file, ok := arguments[0].(*File)
+				if !ok {
+					return nil, errors.New("readLine: argument 1 must be a file.")
+				}
+
+				line, err := file.readLine()
+				if err != nil {
+					return nil, err
+				}
+
+				return interpreter.allocate(line)
+			},
+		},
+		{
+			Name:   "close",
+			Params: 1,
+			Function: func(interpreter *Interpreter, arguments []any) (any, error) {
+				if c, ok := arguments[0].(*Channel); ok {
+					closeChannel(c)
+					return nil, nil
+				}
+
+				file, ok := arguments[0].(*File)
+				if !ok {
+					return nil, errors.New("close: argument 1 must be a file or a channel.")
+				}
+
+				if file.file == nil {
+					return nil, nil
+				}
+
+				err := file.file.Close()
+				file.file = nil
+				return nil, err
+			},
+		},
+	}
+}
@@ -0,0 +1,110 @@
+package interpreter
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestFileAccess(t *testing.T) {
+	root := t.TempDir()
+	outside := t.TempDir()
+
+	err := os.Symlink(outside, filepath.Join(root, "link"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		files         *FileAccess
+		path          string
+		write         bool
+		expectedError bool
+	}{
+		{nil, filepath.Join(root, "a.txt"), false, true},
+		{&FileAccess{}, filepath.Join(outside, "a.txt"), true, false},
+		{&FileAccess{Roots: []string{root}}, filepath.Join(root, "a.txt"), true, false},
+		{&FileAccess{Roots: []string{root}}, filepath.Join(root, "new", "a.txt"), true, false},
+		{&FileAccess{Roots: []string{root}}, filepath.Join(outside, "a.txt"), false, true},
+		{&FileAccess{Roots: []string{root}}, filepath.Join(root, "..", "a.txt"), false, true},
+		{&FileAccess{Roots: []string{root}}, filepath.Join(root, "link", "a.txt"), false, true},
+		{&FileAccess{ReadOnly: true}, filepath.Join(root, "a.txt"), false, false},
+		{&FileAccess{ReadOnly: true}, filepath.Join(root, "a.txt"), true, true},
+	}
+
+	for i, tt := range tests {
+		interpreter := New()
+		interpreter.Files = tt.files
+
+		_, err := interpreter.checkFileAccess("test", tt.path, tt.write)
+		if (err != nil) != tt.expectedError {
+			t.Fatalf("tests[%d] - error wrong for %v. expected=%v, got=%v", i, tt.path, tt.expectedError, err)
+		}
+	}
+}
+
+func TestFileNatives(t *testing.T) {
+	tests := []struct {
+		source        string
+		files         *FileAccess
+		expected      string
+		expectedError string
+	}{
+		{`writeFile("a.txt", "one "); appendFile("a.txt", "two"); print readFile("a.txt");`, &FileAccess{}, "one two\n", ""},
+		{`appendFile("new.txt", "x"); print readFile("new.txt");`, &FileAccess{}, "x\n", ""},
+		{`writeFile("a.txt", "x"); print exists("a.txt"); remove("a.txt"); print exists("a.txt");`, &FileAccess{}, "true\nfalse\n", ""},
+		{`
+		writeFile("lines.txt", "one
+two
+
+three");
+		var f = open("lines.txt");
+		var line = readLine(f);
+		while (line != nil) {
+			print "[" + line + "]";
+			line = readLine(f);
+		}
+		close(f);
+		close(f);
+		`, &FileAccess{}, "[one]\n[two]\n[]\n[three]\n", ""},
+		{`writeFile("a.txt", "x"); var f = open("a.txt"); close(f); readLine(f);`, &FileAccess{}, "", "readLine: file is closed."},
+		{`readFile("missing.txt");`, &FileAccess{}, "", "readFile: open "},
+		{`remove("missing.txt");`, &FileAccess{}, "", "remove: "},
+		{`readLine(1);`, &FileAccess{}, "", "readLine: argument 1 must be a file."},
+		{`readFile(1);`, &FileAccess{}, "", "readFile: argument 1 must be a string."},
+
+		{`readFile("a.txt");`, nil, "", "readFile: file access is disabled."},
+		{`open("a.txt");`, nil, "", "open: file access is disabled."},
+		{`writeFile("a.txt", "x");`, &FileAccess{ReadOnly: true}, "", "writeFile: file access is read-only."},
+		{`appendFile("a.txt", "x");`, &FileAccess{ReadOnly: true}, "", "appendFile: file access is read-only."},
+		{`remove("a.txt");`, &FileAccess{ReadOnly: true}, "", "remove: file access is read-only."},
+		{`readFile("../a.txt");`, &FileAccess{Roots: []string{"."}}, "", "readFile: access to ../a.txt is denied."},
+	}
+
+	for i, tt := range tests {
+		var output bytes.Buffer
+
+		// paths are relative to the directory of the
+		// script rather than to the working directory.
+		dir := t.TempDir()
+
+		interpreter := New()
+		interpreter.Dir = dir
+		interpreter.Stdout = &output
+		interpreter.Files = tt.files
+		if tt.files != nil && len(tt.files.Roots) > 0 {
+			interpreter.Files = &FileAccess{Roots: []string{dir}}
+		}
+
+		err := interpret(t, interpreter, tt.source)
+		if tt.expectedError == "" && err != nil || tt.expectedError != "" && (err == nil || !strings.HasPrefix(err.Error(), tt.expectedError)) {
+			t.Fatalf("tests[%d] - error wrong for %v. expected=%q, got=%v", i, tt.source, tt.expectedError, err)
+		}
+
+		if output.String() != tt.expected {
+			t.Fatalf("tests[%d] - output wrong for %v. expected=%q, got=%q", i, tt.source, tt.expected, output.String())
+		}
+	}
+}
@@ -0,0 +1,313 @@
+package interpreter
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// Format strings contain placeholders in braces, which are
+// replaced by the formatted arguments:
+//
+//	{}        the next argument
+//	{1}       the argument at index 1
+//	{name}    the value of name in a map passed as last argument
+//	{{ }}     literal braces
+//
+// A placeholder may end with a specification after a colon:
+//
+//	[[fill]align][0][width][.precision][verb]
+//
+// align is '<', '>' or '^', and a leading 0 pads numbers with
+// zeros after their sign. The precision is the number of
+// digits after the decimal point of numbers, or the maximum
+// length of strings. The verb is one of d (integer), f (fixed
+// point), e (exponent), x, X, o, b (hexadecimal, octal and
+// binary integers) and s (the value as printed).
+
+// formatSpec is the parsed specification of a placeholder.
+type formatSpec struct {
+	fill      rune
+	align     rune
+	zero      bool
+	width     int
+	precision int
+	verb      rune
+}
+
+// parseFormatSpec parses the specification of a placeholder.
+func parseFormatSpec(s string) (formatSpec, error) {
+	spec := formatSpec{
+		fill:      ' ',
+		precision: -1,
+	}
+
+	runes := []rune(s)
+	isAlign := func(r rune) bool {
+		return r == '<' || r == '>' || r == '^'
+	}
+
+	if len(runes) >= 2 && isAlign(runes[1]) {
+		spec.fill, spec.align = runes[0], runes[1]
+		runes = runes[2:]
+	} else if len(runes) >= 1 && isAlign(runes[0]) {
+		spec.align = runes[0]
+		runes = runes[1:]
+	}
+
+	if len(runes) > 0 && runes[0] == '0' {
+		spec.zero = true
+		runes = runes[1:]
+	}
+
+	// number reads the digits at the start of runes.
+	number := func() int {
+		n := 0
+		for len(runes) > 0 && runes[0] >= '0' && runes[0] <= '9' && n < 1000 {
+			n = n*10 + int(runes[0]-'0')
+			runes = runes[1:]
+		}
+
+		return n
+	}
+
+	spec.width = number()
+
+	if len(runes) > 0 && runes[0] == '.' {
+		runes = runes[1:]
+		if len(runes) == 0 || runes[0] < '0' || runes[0] > '9' {
+			return spec, errors.New("expect digits after '.' in format specification")
+		}
+
+		spec.precision = number()
+	}
+
+	if len(runes) == 1 && strings.ContainsRune("dfexXobs", runes[0]) {
+		spec.verb = runes[0]
+		runes = runes[1:]
+	}
+
+	if len(runes) > 0 {
+		return spec, fmt.Errorf("invalid format specification '%v'", s)
+	}
+
+	return spec, nil
+}
+
+// integerBases maps the integer verbs to their base.
+var integerBases = map[rune]int{
+	'd': 10,
+	'x': 16,
+	'X': 16,
+	'o': 8,
+	'b': 2,
+}
+
+// formatValue formats a single value according to spec.
+func formatValue(value any, spec formatSpec) (string, error) {
+	var s string
+	numeric := isNumber(value)
+
+	switch spec.verb {
+	case 'd', 'x', 'X', 'o', 'b':
+		n, ok := toInteger(value)
+		if !ok {
+			return "", fmt.Errorf("verb '%c' requires an integer", spec.verb)
+		}
+
+		s = n.Text(integerBases[spec.verb])
+		if spec.verb == 'X' {
+			s = strings.ToUpper(s)
+		}
+	case 'f', 'e':
+		if !numeric {
+			return "", fmt.Errorf("verb '%c' requires a number", spec.verb)
+		}
+
+		s = strconv.FormatFloat(toFloat(value), byte(spec.verb), spec.precision, 64)
+	default:
+		if numeric && spec.precision >= 0 {
+			s = strconv.FormatFloat(toFloat(value), 'f', spec.precision, 64)
+		} else {
+			var err error
+			s, err = toString(value)
+			if err != nil {
+				return "", err
+			}
+
+			if spec.precision >= 0 && utf8.RuneCountInString(s) > spec.precision {
+				s = string([]rune(s)[:spec.precision])
+			}
+		}
+	}
+
+	padding := spec.width - utf8.RuneCountInString(s)
+	if padding <= 0 {
+		return s, nil
+	}
+
+	if spec.zero && spec.align == 0 && numeric {
+		sign := ""
+		if strings.HasPrefix(s, "-") || strings.HasPrefix(s, "+") {
+			sign, s = s[:1], s[1:]
+		}
+
+		return sign + strings.Repeat("0", padding) + s, nil
+	}
+
+	align := spec.align
+	if align == 0 {
+		align = '<'
+		if numeric {
+			align = '>'
+		}
+	}
+
+	fill := string(spec.fill)
+	switch align {
+	case '>':
+		return strings.Repeat(fill, padding) + s, nil
+	case '^':
+		left := padding / 2
+		return strings.Repeat(fill, left) + s + strings.Repeat(fill, padding-left), nil
+	}
+
+	return s + strings.Repeat(fill, padding), nil
+}
+
+// formatString replaces the placeholders in format
+// with the formatted arguments.
+func formatString(format string, arguments []any) (string, error) {
+	var b strings.Builder
+	next := 0
+
+	for len(format) > 0 {
+		start := strings.IndexAny(format, "{}")
+		if start < 0 {
+			b.WriteString(format)
+			break
+		}
+
+		b.WriteString(format[:start])
+		format = format[start:]
+
+		if strings.HasPrefix(format, "{{") || strings.HasPrefix(format, "}}") {
+			b.WriteByte(format[0])
+			format = format[2:]
+			continue
+		}
+
+		if format[0] == '}' {
+			return "", errors.New("single '}' in format string")
+		}
+
+		end := strings.IndexByte(format, '}')
+		if end < 0 {
+			return "", errors.New("unclosed '{' in format string")
+		}
+
+		field := format[1:end]
+		format = format[end+1:]
+
+		name, specification, _ := strings.Cut(field, ":")
+
+		var value any
+		switch index, err := strconv.Atoi(name); {
+		case name == "":
+			if next >= len(arguments) {
+				return "", fmt.Errorf("missing argument for placeholder %v", next)
+			}
+
+			value = arguments[next]
+			next++
+		case err == nil:
+			if index < 0 || index >= len(arguments) {
+				return "", fmt.Errorf("missing argument for placeholder %v", index)
+			}
+
+			value = arguments[index]
+		default:
+			var values *Map
+			if len(arguments) > 0 {
+				values, _ = arguments[len(arguments)-1].(*Map)
+			}
+
+			var ok bool
+			if values != nil {
+				value, ok = values.Get(name)
+			}
+
+			if !ok {
+				return "", fmt.Errorf("missing value for placeholder %v", name)
+			}
+		}
+
+		spec, err := parseFormatSpec(specification)
+		if err != nil {
+			return "", err
+		}
+
+		s, err := formatValue(value, spec)
+		if err != nil {
+			return "", fmt.Errorf("placeholder {%v}: %v", field, err)
+		}
+
+		b.WriteString(s)
+	}
+
+	return b.String(), nil
+}
+
+// formatNative creates a native taking a format string
+// and its arguments.
+func formatNative(name string, f func(interpreter *Interpreter, s string) (any, error)) *NativeFunction {
+	return &NativeFunction{
+		Name:   name,
+		Params: Variadic,
+		Function: func(interpreter *Interpreter, arguments []any) (any, error) {
+			if len(arguments) == 0 {
+				return nil, fmt.Errorf("%v: expected a format string.", name)
+			}
+
+			format, err := stringArgument(name, arguments, 0)
+			if err != nil {
+				return nil, err
+			}
+
+			s, err := formatString(format, arguments[1:])
+			if err != nil {
+				return nil, fmt.Errorf("%v: %v.", name, err)
+			}
+
+			return f(interpreter, s)
+		},
+	}
+}
+
+// formatNatives returns the natives formatting values.
+func formatNatives() []*NativeFunction {
+	return []*NativeFunction{
+		formatNative("format", func(interpreter *Interpreter, s string) (any, error) {
+			return interpreter.allocate(s)
+		}),
+		formatNative("printf", func(interpreter *Interpreter, s string) (any, error) {
+			fmt.Fprint(interpreter.stdout(), s)
+			return nil, nil
+		}),
+		{
+			Name:   "write",
+			Params: 1,
+			Function: func(interpreter *Interpreter, arguments []any) (any, error) {
+				s, err := toString(arguments[0])
+				if err != nil {
+					return nil, fmt.Errorf("write: %v.", err)
+				}
+
+				fmt.Fprint(interpreter.stdout(), s)
+				return nil, nil
+			},
+		},
+	}
+}
@@ -0,0 +1,47 @@
+package interpreter
+
+import (
+	"testing"
+)
+
+func TestFormat(t *testing.T) {
+	values := NewMap()
+	values.Set("name", "golox")
+
+	tests := []struct {
+		format        string
+		arguments     []any
+		expected      string
+		expectedError bool
+	}{
+		{"{} + {} = {}", []any{int64(1), int64(2), int64(3)}, "1 + 2 = 3", false},
+		{"{1} {0}", []any{"a", "b"}, "b a", false},
+		{"{{}}", nil, "{}", false},
+		{"hello {name}", []any{values}, "hello golox", false},
+		{"[{:5}]", []any{"ab"}, "[ab   ]", false},
+		{"[{:5}]", []any{int64(42)}, "[   42]", false},
+		{"[{:*^6}]", []any{"ab"}, "[**ab**]", false},
+		{"[{:05}]", []any{int64(-42)}, "[-0042]", false},
+		{"{:.2f}", []any{3.14159}, "3.14", false},
+		{"{:.2}", []any{int64(2)}, "2.00", false},
+		{"{:.3}", []any{"abcdef"}, "abc", false},
+		{"{:x} {:b}", []any{int64(255), 5.0}, "ff 101", false},
+		{"{}", nil, "", true},
+		{"{name}", []any{"golox"}, "", true},
+		{"{", nil, "", true},
+		{"}", nil, "", true},
+		{"{:d}", []any{1.5}, "", true},
+		{"{:y}", []any{int64(1)}, "", true},
+	}
+
+	for i, tt := range tests {
+		s, err := formatString(tt.format, tt.arguments)
+		if (err != nil) != tt.expectedError {
+			t.Fatalf("tests[%d] - error wrong for %q. expected=%v, got=%v", i, tt.format, tt.expectedError, err)
+		}
+
+		if s != tt.expected {
+			t.Fatalf("tests[%d] - result wrong for %q. expected=%q, got=%q", i, tt.format, tt.expected, s)
+		}
+	}
+}
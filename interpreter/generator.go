@@ -0,0 +1,207 @@
+package interpreter
+
+import (
+	"errors"
+	"fmt"
+	"golox/statement"
+)
+
+// A generator runs the body of its function on its own
+// goroutine, which is suspended at every yield until the
+// next value is requested. The generator and the task
+// calling next take turns: only one of them runs at a
+// time, the other waiting with the lock of the scheduler
+// released.
+//
+// Generators that are never exhausted, for instance when
+// a loop over them breaks, keep their goroutine until the
+// run of the script ends, which stops them like tasks.
+
+// generatorResult is sent by a generator when it
+// yields a value or when its body finishes.
+type generatorResult struct {
+	value any
+	done  bool
+	err   error
+}
+
+// Generator is returned by calls to functions
+// containing a yield statement.
+type Generator struct {
+	Name string
+
+	interpreter *Interpreter
+	body        []statement.Stmt
+	environment Environment
+
+	// started, running and done are only accessed
+	// by the tasks holding the lock.
+	started bool
+	running bool
+	done    bool
+
+	resume  chan struct{}
+	results chan generatorResult
+}
+
+func (g *Generator) String() string {
+	return fmt.Sprintf("<generator %v>", g.Name)
+}
+
+// newGenerator creates a generator running body in
+// environment, called from interpreter.
+func (i *Interpreter) newGenerator(name string, body []statement.Stmt, environment Environment) *Generator {
+	generator := &Generator{
+		Name:        name,
+		body:        body,
+		environment: environment,
+		resume:      make(chan struct{}),
+		results:     make(chan generatorResult),
+	}
+
+	generator.interpreter = i.newTask()
+	generator.interpreter.generator = generator
+
+	return generator
+}
+
+// run runs the body of the generator
+// once it is first resumed.
+func (g *Generator) run() {
+	i := g.interpreter
+
+	select {
+	case <-g.resume:
+	case <-i.context().Done():
+		return
+	}
+
+	i.scheduler.lock.Lock()
+	_, err := i.ExecuteBlock(g.body, g.environment)
+	i.scheduler.lock.Unlock()
+
+	select {
+	case g.results <- generatorResult{done: true, err: err}:
+	case <-i.context().Done():
+	}
+}
+
+// next resumes the generator until it yields a value or
+// finishes, returning nil once it is exhausted. caller
+// is the interpreter of the task calling next.
+func (g *Generator) next(caller *Interpreter) (any, error) {
+	if g.done {
+		return nil, nil
+	}
+
+	if g.running {
+		return nil, errors.New("next: generator is already running.")
+	}
+
+	g.running = true
+	defer func() {
+		g.running = false
+	}()
+
+	if !g.started {
+		g.started = true
+		go g.run()
+	}
+
+	var result generatorResult
+	var err error
+	caller.blocking(func() {
+		select {
+		case g.resume <- struct{}{}:
+		case <-caller.context().Done():
+			err = caller.stopped()
+			return
+		}
+
+		select {
+		case result = <-g.results:
+		case <-caller.context().Done():
+			err = caller.stopped()
+		}
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	if result.done {
+		g.done = true
+		return nil, result.err
+	}
+
+	return result.value, nil
+}
+
+// VisitYieldStmt hands a value to the task that resumed
+// the generator, and waits to be resumed again.
+func (i *Interpreter) VisitYieldStmt(stmt *statement.Yield) (any, error) {
+	if i.generator == nil {
+		return nil, errors.New("Can only yield inside a generator.")
+	}
+
+	var value any
+	var err error
+
+	if stmt.Value != nil {
+		value, err = i.evaluate(stmt.Value)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	g := i.generator
+	i.blocking(func() {
+		select {
+		case g.results <- generatorResult{value: value}:
+		case <-i.context().Done():
+			err = i.stopped()
+			return
+		}
+
+		select {
+		case <-g.resume:
+		case <-i.context().Done():
+			err = i.stopped()
+		}
+	})
+
+	return nil, err
+}
+
+// generatorNatives returns the natives
+// consuming generators.
+func generatorNatives() []*NativeFunction {
+	return []*NativeFunction{
+		{
+			Name:   "next",
+			Params: 1,
+			Function: func(interpreter *Interpreter, arguments []any) (any, error) {
+				generator, ok := arguments[0].(*Generator)
+				if !ok {
+					return nil, errors.New("next: argument 1 must be a generator.")
+				}
+
+				return generator.next(interpreter)
+			},
+		},
+		{
+			// done checks if a generator is exhausted,
+			// which next reports by returning nil.
+			Name:   "done",
+			Params: 1,
+			Function: func(interpreter *Interpreter, arguments []any) (any, error) {
+				generator, ok := arguments[0].(*Generator)
+				if !ok {
+					return nil, errors.New("done: argument 1 must be a generator.")
+				}
+
+				return generator.done, nil
+			},
+		},
+	}
+}
@@ -0,0 +1,122 @@
+package interpreter
+
+import (
+	"bytes"
+	"runtime"
+	"testing"
+	"time"
+)
+
+func TestGenerators(t *testing.T) {
+	tests := []struct {
+		source        string
+		expected      string
+		expectedError bool
+	}{
+		{`
+		fun count(n) {
+			var i = 0;
+			while (i < n) {
+				yield i;
+				i++;
+			}
+		}
+		var g = count(2);
+		print next(g);
+		print next(g);
+		print done(g);
+		print next(g);
+		print done(g);
+		print next(g);
+		`, "0\n1\nfalse\n<nil>\ntrue\n<nil>\n", false},
+		{`
+		fun naturals() {
+			var n = 0;
+			while (true) yield n++;
+		}
+		fun squares(numbers) {
+			while (true) {
+				var n = next(numbers);
+				yield n * n;
+			}
+		}
+		var s = squares(naturals());
+		next(s);
+		next(s);
+		print next(s);
+		`, "4\n", false},
+		{`
+		fun twice(x) {
+			yield x;
+			yield x;
+		}
+		var a = twice("a");
+		var b = twice("b");
+		print next(a) + next(b) + next(a) + next(b);
+		`, "abab\n", false},
+		{`
+		fun failing() {
+			yield 1;
+			yield nil + 1;
+		}
+		var g = failing();
+		print next(g);
+		next(g);
+		`, "1\n", true},
+		{`
+		fun recursive() {
+			yield next(g);
+		}
+		var g = recursive();
+		next(g);
+		`, "", true},
+	}
+
+	for i, tt := range tests {
+		var output bytes.Buffer
+
+		interpreter := New()
+		interpreter.Stdout = &output
+
+		err := interpret(t, interpreter, tt.source)
+		if (err != nil) != tt.expectedError {
+			t.Fatalf("tests[%d] - error wrong. expected=%v, got=%v", i, tt.expectedError, err)
+		}
+
+		if output.String() != tt.expected {
+			t.Fatalf("tests[%d] - output wrong. expected=%q, got=%q", i, tt.expected, output.String())
+		}
+	}
+}
+
+func TestGeneratorsStopped(t *testing.T) {
+	program, err := Compile(`
+	fun naturals() {
+		var i = 0;
+		while (true) yield i++;
+	}
+	for (n in naturals()) if (n == 3) break;
+	`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	goroutines := runtime.NumGoroutine()
+
+	err = New().Run(program)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the goroutine of the generator left suspended by
+	// break ends once the script ends, even though the
+	// context of the interpreter is never done.
+	deadline := time.Now().Add(time.Second)
+	for runtime.NumGoroutine() > goroutines {
+		if time.Now().After(deadline) {
+			t.Fatalf("generator still running. goroutines=%v, expected=%v", runtime.NumGoroutine(), goroutines)
+		}
+
+		time.Sleep(time.Millisecond)
+	}
+}
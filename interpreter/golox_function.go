@@ -7,6 +7,10 @@ import (
 
 type GoloxFunction struct {
 	Declaration statement.Function
+
+	// Closure is the environment the function was
+	// declared in, which its body can access.
+	Closure Environment
 }
 
 func (g *GoloxFunction) Call(
@@ -14,28 +18,127 @@ func (g *GoloxFunction) Call(
 	arguments []any,
 ) (any, error) {
 	environment := NewEnvironment(
-		fInterpreter.Globals,
+		g.Closure,
 	)
 
-	for i := 0; i < len(g.Declaration.Params); i++ {
-		environment.Define(
-			g.Declaration.Params[i].Lexeme,
-			arguments[i],
-		)
+	err := g.bind(fInterpreter, environment, arguments)
+	if err != nil {
+		return nil, err
 	}
 
-	var res any = nil
+	if g.Declaration.Generator {
+		generator := fInterpreter.newGenerator(
+			g.Declaration.Name.Lexeme,
+			g.Declaration.Body,
+			environment,
+		)
+
+		return generator, nil
+	}
 
 	res, err := fInterpreter.ExecuteBlock(
 		g.Declaration.Body,
 		environment,
 	)
 
-	return res, err
+	// functions without a return statement return nil.
+	if v, ok := res.(returnValue); ok {
+		return v.getValue(), err
+	}
+
+	return nil, err
+}
+
+// bind defines the parameters in the environment of a
+// call. Parameters without an argument take their default
+// value, evaluated in the environment so that it can refer
+// to the parameters before them.
+func (g *GoloxFunction) bind(
+	fInterpreter *Interpreter,
+	environment Environment,
+	arguments []any,
+) error {
+	params := g.Declaration.Params
+	if g.Declaration.Rest {
+		params = params[:len(params)-1]
+	}
+
+	for i, param := range params {
+		if i < len(arguments) && arguments[i] != (missingArgument{}) {
+			environment.Define(param.Lexeme, arguments[i])
+			continue
+		}
+
+		if i >= len(g.Declaration.Defaults) || g.Declaration.Defaults[i] == nil {
+			return fmt.Errorf("Missing argument for parameter %v.", param.Lexeme)
+		}
+
+		previous := fInterpreter.Environment
+		fInterpreter.Environment = environment
+		value, err := fInterpreter.evaluate(g.Declaration.Defaults[i])
+		fInterpreter.Environment = previous
+
+		if err != nil {
+			return err
+		}
+
+		environment.Define(param.Lexeme, value)
+	}
+
+	if g.Declaration.Rest {
+		rest := &Array{}
+		if len(arguments) > len(params) {
+			rest.Elements = append(rest.Elements, arguments[len(params):]...)
+		}
+
+		_, err := fInterpreter.allocate(rest)
+		if err != nil {
+			return err
+		}
+
+		environment.Define(g.Declaration.Params[len(params)].Lexeme, rest)
+	}
+
+	return nil
 }
 
-func (g *GoloxFunction) Arity() int {
-	return len(g.Declaration.Params)
+// Arity returns the number of parameters without
+// default value, and the number of parameters or
+// Variadic for functions with a rest parameter.
+func (g *GoloxFunction) Arity() (int, int) {
+	min := 0
+	for i := range g.Declaration.Params {
+		if g.Declaration.Rest && i == len(g.Declaration.Params)-1 {
+			break
+		}
+
+		if i < len(g.Declaration.Defaults) && g.Declaration.Defaults[i] != nil {
+			break
+		}
+
+		min++
+	}
+
+	if g.Declaration.Rest {
+		return min, Variadic
+	}
+
+	return min, len(g.Declaration.Params)
+}
+
+// Parameters returns the names of the parameters,
+// except the rest parameter.
+func (g *GoloxFunction) Parameters() []string {
+	names := []string{}
+	for i, param := range g.Declaration.Params {
+		if g.Declaration.Rest && i == len(g.Declaration.Params)-1 {
+			break
+		}
+
+		names = append(names, param.Lexeme)
+	}
+
+	return names
 }
 
 func (g *GoloxFunction) ToString() string {
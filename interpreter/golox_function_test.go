@@ -0,0 +1,59 @@
+package interpreter
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestParameters(t *testing.T) {
+	tests := []struct {
+		source        string
+		expected      string
+		expectedError string
+	}{
+		{`fun f(a, b = 2) { print a + b; } f(1); f(1, 3);`, "3\n4\n", ""},
+		{`fun f(a, b = a * 2) { print b; } f(3);`, "6\n", ""},
+		{`
+		var calls = 0;
+		fun next() { return calls++; }
+		fun f(a = next()) { return a; }
+		f(); f(); print f();
+		`, "2\n", ""},
+		{`fun f(a, ...rest) { print rest; } f(1); f(1, 2, 3);`, "[]\n[2, 3]\n", ""},
+		{`fun f(...rest) { print len(rest); } f(); f(nil, nil);`, "0\n2\n", ""},
+		{`fun f(a, b) { print a - b; } f(b: 1, a: 3);`, "2\n", ""},
+		{`fun f(a, b = 2, c = 3) { print a + b + c; } f(1, c: 10);`, "13\n", ""},
+		{`fun f(a, ...rest) { print a; print rest; } f(a: 1);`, "1\n[]\n", ""},
+		{`fun f(a, b = 2) { yield a; yield b; } var g = f(b: 3, a: 1); print next(g) + next(g);`, "4\n", ""},
+		{`fun f(a, b) { print a + b; } wait(spawn f(1, b: 2));`, "3\n", ""},
+		{`fun f(a, b) {} f(1);`, "", "Expected 2 arguments but got 1."},
+		{`fun f(a, b = 2) {} f(1, 2, 3);`, "", "Expected 1 to 2 arguments but got 3."},
+		{`fun f(a, b, ...rest) {} f(1);`, "", "Expected at least 2 arguments but got 1."},
+		{`fun f(a, b) {} f(b: 2);`, "", "Missing argument for parameter a."},
+		{`fun f(a) {} f(1, a: 2);`, "", "Argument a passed more than once."},
+		{`fun f(a) {} f(a: 1, a: 2);`, "", "Argument a passed more than once."},
+		{`fun f(a) {} f(b: 2);`, "", "Unknown parameter b."},
+		{`fun f(...rest) {} f(rest: 1);`, "", "Unknown parameter rest."},
+		{`len(value: "a");`, "", "Callee does not take named arguments."},
+	}
+
+	for i, tt := range tests {
+		var output bytes.Buffer
+
+		interpreter := New()
+		interpreter.Stdout = &output
+
+		err := interpret(t, interpreter, tt.source)
+		if tt.expectedError == "" && err != nil {
+			t.Fatalf("tests[%d] - unexpected error: %v", i, err)
+		}
+
+		if tt.expectedError != "" && (err == nil || err.Error() != tt.expectedError) {
+			t.Fatalf("tests[%d] - error wrong. expected=%q, got=%v", i, tt.expectedError, err)
+		}
+
+		if output.String() != tt.expected {
+			t.Fatalf("tests[%d] - output wrong. expected=%q, got=%q", i, tt.expected, output.String())
+		}
+	}
+}
@@ -1,14 +1,20 @@
 package interpreter
 
 import (
+	"bufio"
+	"context"
 	"errors"
 	"fmt"
 	"golox/ast"
 	"golox/statement"
 	"golox/token"
-	"os"
+	"io"
+	"math/rand"
+	"regexp"
 )
 
+// returnValue is the result of a return statement, which
+// the enclosing statements pass up to the function.
 type returnValue struct {
 	value any
 }
@@ -17,14 +23,208 @@ func (r *returnValue) getValue() any {
 	return r.value
 }
 
+// breakLoop and continueLoop are the results of break and
+// continue statements, which the enclosing statements
+// pass up to the loop.
+type breakLoop struct{}
+type continueLoop struct{}
+
+// isJump checks if the result of a statement
+// interrupts the enclosing statements.
+func isJump(res any) bool {
+	switch res.(type) {
+	case returnValue, breakLoop, continueLoop:
+		return true
+	}
+
+	return false
+}
+
 type GoloxCallable interface {
-	Arity() int
+	// Arity returns the minimum and maximum number of
+	// arguments the callable expects, max being Variadic
+	// when there is no maximum.
+	Arity() (min int, max int)
+
+	// Parameters returns the names of the parameters
+	// named arguments can be passed to, in order, or
+	// nil if the callable takes no named arguments.
+	Parameters() []string
+
 	Call(interpreter *Interpreter, argumenst []any) (any, error)
 }
 
+// Interpreter executes golox programs. An Interpreter
+// must not be used by several goroutines at the same
+// time, but many interpreters can run concurrently,
+// including interpreters running the same Program.
 type Interpreter struct {
 	Environment Environment
 	Globals     Environment
+
+	// Exports contains the names of the global
+	// variables exported by the script.
+	Exports map[string]bool
+
+	// Loader loads the modules imported by the script.
+	Loader *ModuleLoader
+
+	// modules contains the modules imported by the script,
+	// by canonical path. It is shared with the tasks of the
+	// script and the modules it imports.
+	modules map[string]*moduleEntry
+
+	// imports contains the files of the modules imported
+	// from the script to the module run by the interpreter,
+	// which are still being loaded, to detect cycles.
+	imports []string
+
+	// Dir is the directory imports and file paths are
+	// relative to, usually the directory of the script.
+	Dir string
+
+	// Files controls which files the script can access,
+	// including the modules it imports.
+	// File access is disabled if it is nil.
+	Files *FileAccess
+
+	// Process allows the script to run commands, to change
+	// the working directory and to access the environment
+	// variables. It is disabled by default.
+	Process bool
+
+	// env contains the environment variables and the working
+	// directory of the script, which start as those of the
+	// process. It is shared with the tasks of the script
+	// and the modules it imports.
+	env *processEnv
+
+	// Args contains the arguments passed to the script.
+	Args []string
+
+	// Stdin is the input read by input and readLine.
+	// Scripts have no input if it is nil.
+	Stdin *bufio.Reader
+
+	// stdin reads the lines of Stdin. It is shared with
+	// the tasks of the script and the modules it imports.
+	stdin *stdinReader
+
+	// Stdout is the output written by print and the other
+	// natives printing values. It defaults to os.Stdout.
+	Stdout io.Writer
+
+	// Random is the source of the random natives,
+	// which scripts can seed for reproducible results.
+	Random *rand.Rand
+
+	// Context stops the script when it is done.
+	Context context.Context
+
+	// run is derived from Context for each run of a script,
+	// and is cancelled when the run ends, stopping the tasks
+	// and generators the script left running.
+	run context.Context
+
+	// Limits restricts the resources used by the script.
+	Limits Limits
+
+	usage *usage
+
+	// depth is the number of nested calls
+	// of the task run by the interpreter.
+	depth int
+
+	scheduler *scheduler
+
+	// generator is the generator whose body the
+	// interpreter runs, if any.
+	generator *Generator
+
+	// patterns caches the compiled regular expressions.
+	patterns map[string]*regexp.Regexp
+}
+
+// New creates an Interpreter with a new global environment.
+func New() *Interpreter {
+	// initialize global environment here for
+	// a fixed reference to the outermost global
+	// environment for the interpreter.
+	globals := Environment{
+		Enclosing: nil,
+		Values:    make(map[string]any),
+		Constants: make(map[string]bool),
+	}
+
+	globals.Define("math", newMathModule())
+	globals.Define("time", newTimeModule())
+	globals.Define("clock", clockNative())
+
+	for _, native := range stringNatives() {
+		globals.Define(native.Name, native)
+	}
+
+	for _, native := range collectionNatives() {
+		globals.Define(native.Name, native)
+	}
+
+	for _, native := range jsonNatives() {
+		globals.Define(native.Name, native)
+	}
+
+	for _, native := range formatNatives() {
+		globals.Define(native.Name, native)
+	}
+
+	for _, native := range regexpNatives() {
+		globals.Define(native.Name, native)
+	}
+
+	for _, native := range randomNatives() {
+		globals.Define(native.Name, native)
+	}
+
+	for _, native := range fileNatives() {
+		globals.Define(native.Name, native)
+	}
+
+	for _, native := range iterationNatives() {
+		globals.Define(native.Name, native)
+	}
+
+	for _, native := range generatorNatives() {
+		globals.Define(native.Name, native)
+	}
+
+	for _, native := range taskNatives() {
+		globals.Define(native.Name, native)
+	}
+
+	for _, native := range processNatives() {
+		globals.Define(native.Name, native)
+	}
+
+	for _, native := range systemNatives() {
+		globals.Define(native.Name, native)
+	}
+
+	return &Interpreter{
+		Environment: globals,
+		Globals:     globals,
+		Exports:     make(map[string]bool),
+		Loader:      NewModuleLoader(nil),
+		modules:     make(map[string]*moduleEntry),
+		stdin:       newStdinReader(),
+		env:         &processEnv{},
+		Dir:         ".",
+		Random:      newRandom(),
+		Limits: Limits{
+			CallDepth: DefaultCallDepth,
+		},
+		usage:     &usage{},
+		scheduler: &scheduler{},
+		patterns:  make(map[string]*regexp.Regexp),
+	}
 }
 
 // isTruthy checks if an object is truthy or falsey.
@@ -51,13 +251,27 @@ func (i *Interpreter) isEqual(a any, b any) bool {
 		return false
 	}
 
+	// numbers of different kinds are equal
+	// when they have the same value.
+	if isNumber(a) && isNumber(b) {
+		result, ok := compareNumbers(a, b)
+		return ok && result == 0
+	}
+
+	// times are equal when they are the same
+	// instant, whatever their location.
+	if t, ok := a.(*Time); ok {
+		u, ok := b.(*Time)
+		return ok && t.Time.Equal(u.Time)
+	}
+
 	return a == b
 }
 
 // checkNumberOperand checks if an operand is a number.
-// a number is defined as numbers that can be parsed to Go's float64.
+// a number is either a float or an integer.
 func (i *Interpreter) checkNumberOperand(operator token.Token, operand any) error {
-	if _, ok := operand.(float64); ok {
+	if isNumber(operand) {
 		return nil
 	}
 
@@ -65,12 +279,10 @@ func (i *Interpreter) checkNumberOperand(operator token.Token, operand any) erro
 }
 
 // checkNumberOperands checks if two operands are numbers.
-// a number is defined as numbers that can be parsed to Go's float64.
+// a number is either a float or an integer.
 func (i *Interpreter) checkNumberOperands(operator token.Token, left any, right any) error {
-	if _, ok := left.(float64); ok {
-		if _, ok := right.(float64); ok {
-			return nil
-		}
+	if isNumber(left) && isNumber(right) {
+		return nil
 	}
 
 	return errors.New("operands must be numbers")
@@ -86,6 +298,21 @@ func (i *Interpreter) VisitGroupingExpr(expr *ast.Grouping) (any, error) {
 	return i.evaluate(expr.Expression)
 }
 
+// VisitGetExpr evaluates a property access. Only
+// modules have properties, which are their exports.
+func (i *Interpreter) VisitGetExpr(expr *ast.Get) (any, error) {
+	object, err := i.evaluate(expr.Object)
+	if err != nil {
+		return nil, err
+	}
+
+	if module, ok := object.(*Module); ok {
+		return module.Get(expr.Name)
+	}
+
+	return nil, errors.New("Only modules have properties.")
+}
+
 func (i *Interpreter) VisitVariableExpr(expr *ast.Variable) (any, error) {
 	return i.Environment.Get(expr.Name)
 }
@@ -104,6 +331,32 @@ func (i *Interpreter) VisitAssignExpr(expr *ast.Assign) (any, error) {
 	return value, nil
 }
 
+// VisitPostfixExpr evaluates a postfix increment or
+// decrement, returning the value before the update.
+func (i *Interpreter) VisitPostfixExpr(expr *ast.Postfix) (any, error) {
+	value, err := i.Environment.Get(expr.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	err = i.checkNumberOperand(expr.Operator, value)
+	if err != nil {
+		return nil, err
+	}
+
+	updated := addNumbers(value, int64(1))
+	if expr.Operator.Type == token.MINUS_MINUS {
+		updated = subtractNumbers(value, int64(1))
+	}
+
+	err = i.Environment.assign(expr.Name, updated)
+	if err != nil {
+		return nil, err
+	}
+
+	return value, nil
+}
+
 func (i *Interpreter) VisitLogicalExpr(expr *ast.Logical) (any, error) {
 	left, err := i.evaluate(expr.Left)
 	if err != nil {
@@ -123,42 +376,161 @@ func (i *Interpreter) VisitLogicalExpr(expr *ast.Logical) (any, error) {
 	return i.evaluate(expr.Right)
 }
 
+// VisitConditionalExpr evaluates a conditional expression.
+// Only the branch that is selected is evaluated.
+func (i *Interpreter) VisitConditionalExpr(expr *ast.Conditional) (any, error) {
+	condition, err := i.evaluate(expr.Condition)
+	if err != nil {
+		return nil, err
+	}
+
+	if i.isTruthy(condition) {
+		return i.evaluate(expr.ThenBranch)
+	}
+
+	return i.evaluate(expr.ElseBranch)
+}
+
+// VisitCoalesceExpr evaluates a null-coalescing expression.
+// The right operand is only evaluated if the left one is nil.
+func (i *Interpreter) VisitCoalesceExpr(expr *ast.Coalesce) (any, error) {
+	left, err := i.evaluate(expr.Left)
+	if err != nil {
+		return nil, err
+	}
+
+	if left != nil {
+		return left, nil
+	}
+
+	return i.evaluate(expr.Right)
+}
+
 func (i *Interpreter) VisitCallExpr(expr *ast.Call) (any, error) {
-	callee, err := i.evaluate(expr.Callee)
+	function, arguments, err := i.prepareCall(expr)
+	if err != nil {
+		return nil, err
+	}
+
+	fnCall, err := i.call(function, arguments)
 	if err != nil {
 		return nil, err
 	}
 
+	return fnCall, nil
+}
+
+// prepareCall evaluates the callee and the arguments
+// of a call, checking the number of arguments.
+func (i *Interpreter) prepareCall(expr *ast.Call) (GoloxCallable, []any, error) {
+	callee, err := i.evaluate(expr.Callee)
+	if err != nil {
+		return nil, nil, err
+	}
+
 	arguments := []any{}
 	for _, argument := range expr.Arguments {
 		res, err := i.evaluate(argument)
 		if err != nil {
-			return nil, err
+			return nil, nil, err
 		}
 
 		arguments = append(arguments, res)
 	}
 
+	named := []any{}
+	for _, argument := range expr.Named {
+		res, err := i.evaluate(argument.Value)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		named = append(named, res)
+	}
+
 	if _, ok := callee.(GoloxCallable); !ok {
-		return nil, fmt.Errorf("Callee is not a golox callable.", expr.Callee)
+		return nil, nil, errors.New("Callee is not a golox callable.")
 	}
 
 	var function GoloxCallable = callee.(GoloxCallable)
 
-	if len(arguments) != function.Arity() {
-		return nil, fmt.Errorf("Expected %v arguments but got %v.", function.Arity(), len(arguments))
+	if len(expr.Named) > 0 {
+		arguments, err = bindNamedArguments(function, arguments, expr.Named, named)
+		if err != nil {
+			return nil, nil, err
+		}
 	}
 
-	fnCall, err := function.Call(i, arguments)
-	if err != nil {
-		return nil, err
+	min, max := function.Arity()
+	if len(arguments) < min || (max != Variadic && len(arguments) > max) {
+		return nil, nil, arityError(min, max, len(arguments))
 	}
 
-	return fnCall, nil
+	return function, arguments, nil
+}
+
+// missingArgument is the value of the parameters skipped
+// by named arguments, which take their default value.
+type missingArgument struct{}
+
+// bindNamedArguments places the named arguments after the
+// positional ones, at the index of their parameter.
+func bindNamedArguments(
+	function GoloxCallable,
+	arguments []any,
+	named []ast.NamedArgument,
+	values []any,
+) ([]any, error) {
+	parameters := function.Parameters()
+	if parameters == nil {
+		return nil, errors.New("Callee does not take named arguments.")
+	}
+
+	for i, argument := range named {
+		name := argument.Name.Lexeme
+		index := -1
+		for n, parameter := range parameters {
+			if parameter == name {
+				index = n
+			}
+		}
+
+		if index < 0 {
+			return nil, fmt.Errorf("Unknown parameter %v.", name)
+		}
+
+		if index < len(arguments) && arguments[index] != (missingArgument{}) {
+			return nil, fmt.Errorf("Argument %v passed more than once.", name)
+		}
+
+		for len(arguments) <= index {
+			arguments = append(arguments, missingArgument{})
+		}
+
+		arguments[index] = values[i]
+	}
+
+	return arguments, nil
+}
+
+// arityError reports a wrong number of arguments.
+func arityError(min int, max int, got int) error {
+	switch {
+	case min == max:
+		return fmt.Errorf("Expected %v arguments but got %v.", min, got)
+	case max == Variadic:
+		return fmt.Errorf("Expected at least %v arguments but got %v.", min, got)
+	default:
+		return fmt.Errorf("Expected %v to %v arguments but got %v.", min, max, got)
+	}
 }
 
 // evaluate evaluates an expression.
 func (i *Interpreter) evaluate(expr ast.Expr) (any, error) {
+	err := i.step()
+	if err != nil {
+		return nil, err
+	}
 
 	// error is to detect runtime errors
 	res, err := expr.Accept(i)
@@ -176,8 +548,6 @@ func (i *Interpreter) VisitUnaryExpr(expr *ast.Unary) (any, error) {
 		return nil, err
 	}
 
-	v, _ := right.(float64)
-
 	switch expr.Operator.Type {
 	case token.MINUS:
 		err := i.checkNumberOperand(expr.Operator, right)
@@ -185,7 +555,14 @@ func (i *Interpreter) VisitUnaryExpr(expr *ast.Unary) (any, error) {
 			return nil, err
 		}
 
-		return -v, nil
+		return negateNumber(right), nil
+	case token.TILDE:
+		err := i.checkNumberOperand(expr.Operator, right)
+		if err != nil {
+			return nil, err
+		}
+
+		return complementNumber(right)
 	case token.BANG:
 		return !i.isTruthy(right), nil
 	}
@@ -215,40 +592,42 @@ func (i *Interpreter) VisitBinaryExpr(expr *ast.Binary) (any, error) {
 		if err != nil {
 			return nil, err
 		}
-		return left.(float64) > right.(float64), nil
+		result, ok := compareNumbers(left, right)
+		return ok && result > 0, nil
 	case token.GREATER_EQUAL:
 		err := i.checkNumberOperands(expr.Operator, left, right)
 		if err != nil {
 			return nil, err
 		}
-		return left.(float64) >= right.(float64), nil
+		result, ok := compareNumbers(left, right)
+		return ok && result >= 0, nil
 	case token.LESS:
 		err := i.checkNumberOperands(expr.Operator, left, right)
 		if err != nil {
 			return nil, err
 		}
-		return left.(float64) < right.(float64), nil
+		result, ok := compareNumbers(left, right)
+		return ok && result < 0, nil
 	case token.LESS_EQUAL:
 		err := i.checkNumberOperands(expr.Operator, left, right)
 		if err != nil {
 			return nil, err
 		}
-		return left.(float64) <= right.(float64), nil
+		result, ok := compareNumbers(left, right)
+		return ok && result <= 0, nil
 	case token.MINUS:
 		err := i.checkNumberOperands(expr.Operator, left, right)
 		if err != nil {
 			return nil, err
 		}
-		return left.(float64) - right.(float64), nil
+		return i.allocate(subtractNumbers(left, right))
 	case token.PLUS:
 		if vLeft, ok := left.(string); ok {
 			if vRight, ok := right.(string); ok {
-				return vLeft + vRight, nil
-			}
-		} else if vLeft, ok := left.(float64); ok {
-			if vRight, ok := right.(float64); ok {
-				return vLeft + vRight, nil
+				return i.allocate(vLeft + vRight)
 			}
+		} else if isNumber(left) && isNumber(right) {
+			return i.allocate(addNumbers(left, right))
 		}
 
 		return nil, errors.New("operands must be two numbers or two strings")
@@ -257,13 +636,45 @@ func (i *Interpreter) VisitBinaryExpr(expr *ast.Binary) (any, error) {
 		if err != nil {
 			return nil, err
 		}
-		return left.(float64) / right.(float64), nil
+		return divideNumbers(left, right), nil
 	case token.STAR:
 		err := i.checkNumberOperands(expr.Operator, left, right)
 		if err != nil {
 			return nil, err
 		}
-		return left.(float64) * right.(float64), nil
+		return i.allocate(multiplyNumbers(left, right))
+	case token.TILDE_SLASH:
+		err := i.checkNumberOperands(expr.Operator, left, right)
+		if err != nil {
+			return nil, err
+		}
+		return floorDivideNumbers(left, right)
+	case token.PERCENT:
+		err := i.checkNumberOperands(expr.Operator, left, right)
+		if err != nil {
+			return nil, err
+		}
+		return moduloNumbers(left, right)
+	case token.STAR_STAR:
+		err := i.checkNumberOperands(expr.Operator, left, right)
+		if err != nil {
+			return nil, err
+		}
+		result, err := powerNumbers(left, right)
+		if err != nil {
+			return nil, err
+		}
+		return i.allocate(result)
+	case token.AMPERSAND, token.PIPE, token.CARET, token.LESS_LESS, token.GREATER_GREATER:
+		err := i.checkNumberOperands(expr.Operator, left, right)
+		if err != nil {
+			return nil, err
+		}
+		result, err := bitwiseNumbers(expr.Operator.Type, left, right)
+		if err != nil {
+			return nil, err
+		}
+		return i.allocate(result)
 	}
 
 	return nil, nil
@@ -279,7 +690,12 @@ func (i *Interpreter) VisitPrintStmt(stmt *statement.Print) (any, error) {
 		return nil, err
 	}
 
-	fmt.Println(value)
+	s, err := toString(value)
+	if err != nil {
+		return nil, fmt.Errorf("Cannot print value: %v.", err)
+	}
+
+	fmt.Fprintln(i.stdout(), s)
 	return value, nil
 }
 
@@ -294,7 +710,11 @@ func (i *Interpreter) VisitVarStmt(stmt *statement.Variable) (any, error) {
 		}
 	}
 
-	i.Environment.Define(stmt.Name.Lexeme, value)
+	err = i.Environment.declare(stmt.Name, value, stmt.Constant)
+	if err != nil {
+		return nil, err
+	}
+
 	return nil, nil
 }
 
@@ -304,28 +724,26 @@ func (i *Interpreter) VisitBlockStmt(stmt *statement.Block) (any, error) {
 
 func (i *Interpreter) ExecuteBlock(statements []statement.Stmt, environment Environment) (any, error) {
 	previous := i.Environment
+	defer func() {
+		i.Environment = previous
+	}()
 
 	i.Environment = environment
 
-	var res any = nil
-	var err error
-
 	for _, statement := range statements {
-		res, err = i.execute(statement)
+		res, err := i.execute(statement)
 		if err != nil {
 			return nil, err
 		}
 
-		// return when there is early return
-		// in a function
-		if v, ok := res.(returnValue); ok {
-			res = v.getValue()
-			break
+		// stop at a return, a break or a continue,
+		// leaving it to the enclosing statement.
+		if isJump(res) {
+			return res, nil
 		}
 	}
 
-	i.Environment = previous
-	return res, nil
+	return nil, nil
 }
 
 func (i *Interpreter) VisitIfStmt(stmt *statement.If) (any, error) {
@@ -350,11 +768,25 @@ func (i *Interpreter) VisitWhileStmt(stmt *statement.While) (any, error) {
 	}
 
 	for i.isTruthy(res) {
-		_, err = i.execute(stmt.Body)
+		jump, err := i.execute(stmt.Body)
 		if err != nil {
 			return nil, err
 		}
 
+		switch jump.(type) {
+		case returnValue:
+			return jump, nil
+		case breakLoop:
+			return nil, nil
+		}
+
+		if stmt.Increment != nil {
+			_, err = i.evaluate(stmt.Increment)
+			if err != nil {
+				return nil, err
+			}
+		}
+
 		res, err = i.evaluate(stmt.Condition)
 		if err != nil {
 			return nil, err
@@ -367,9 +799,13 @@ func (i *Interpreter) VisitWhileStmt(stmt *statement.While) (any, error) {
 func (i *Interpreter) VisitFunctionStmt(stmt *statement.Function) (any, error) {
 	fun := &GoloxFunction{
 		Declaration: *stmt,
+		Closure:     i.Environment,
 	}
 
-	i.Environment.Define(stmt.Name.Lexeme, fun)
+	err := i.Environment.declare(stmt.Name, fun, false)
+	if err != nil {
+		return nil, err
+	}
 
 	return nil, nil
 }
@@ -383,28 +819,99 @@ func (i *Interpreter) VisitReturnStmt(stmt *statement.Return) (any, error) {
 		if err != nil {
 			return nil, err
 		}
+	}
 
-		if value != nil {
-			value = returnValue{
-				value: value,
-			}
+	return returnValue{
+		value: value,
+	}, nil
+}
+
+func (i *Interpreter) VisitBreakStmt(stmt *statement.Break) (any, error) {
+	return breakLoop{}, nil
+}
+
+func (i *Interpreter) VisitContinueStmt(stmt *statement.Continue) (any, error) {
+	return continueLoop{}, nil
+}
+
+// VisitImportStmt loads a module and binds either the
+// module itself or the imported names. The bindings are
+// constants: they hold a copy of the exported values, so
+// assigning them could never change the module, and the
+// exported constants stay constant.
+func (i *Interpreter) VisitImportStmt(stmt *statement.Import) (any, error) {
+	module, err := i.Loader.Load(stmt.Path.Literal.(string), i)
+	if err != nil {
+		return nil, err
+	}
+
+	if stmt.Alias.Lexeme != "" {
+		return nil, i.Environment.declare(stmt.Alias, module, true)
+	}
+
+	for _, name := range stmt.Names {
+		value, err := module.Get(name)
+		if err != nil {
+			return nil, err
+		}
+
+		err = i.Environment.declare(name, value, true)
+		if err != nil {
+			return nil, err
 		}
 	}
 
-	return value, nil
+	return nil, nil
+}
+
+// VisitExportStmt executes a declaration and
+// exports the declared variable.
+func (i *Interpreter) VisitExportStmt(stmt *statement.Export) (any, error) {
+	_, err := i.execute(stmt.Declaration)
+	if err != nil {
+		return nil, err
+	}
+
+	i.Exports[stmt.Name.Lexeme] = true
+	return nil, nil
 }
 
 func (i *Interpreter) execute(stmt statement.Stmt) (any, error) {
+	err := i.step()
+	if err != nil {
+		return nil, err
+	}
+
 	return stmt.Accept(i)
 }
 
-// Interpret interprets expressions from an AST.
-func (i *Interpreter) Interpret(statements []statement.Stmt) {
+// Interpret interprets expressions from an AST,
+// stopping at the first runtime error.
+func (i *Interpreter) Interpret(statements []statement.Stmt) error {
+	i.scheduler.lock.Lock()
+	defer i.scheduler.lock.Unlock()
+
+	ctx, cancel := context.WithCancel(i.context())
+	previous := i.run
+	i.run = ctx
+
+	defer func() {
+		cancel()
+		i.run = previous
+	}()
+
+	return i.interpret(statements)
+}
+
+// interpret interprets statements for a caller
+// already holding the lock of the scheduler.
+func (i *Interpreter) interpret(statements []statement.Stmt) error {
 	for _, statement := range statements {
 		_, err := i.execute(statement)
 		if err != nil {
-			fmt.Println(err)
-			os.Exit(1)
+			return err
 		}
 	}
+
+	return nil
 }
@@ -0,0 +1,241 @@
+package interpreter
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestReturn(t *testing.T) {
+	tests := []struct {
+		source   string
+		expected string
+	}{
+		{`
+		fun f() {
+			{
+				return 1;
+			}
+			return 2;
+		}
+		print f();
+		`, "1\n"},
+		{`
+		fun f(n) {
+			if (n > 0) {
+				return "positive";
+			}
+			return "other";
+		}
+		print f(1);
+		print f(0);
+		`, "positive\nother\n"},
+		{`
+		fun find(n) {
+			var i = 0;
+			while (true) {
+				if (i == n) return i;
+				i++;
+			}
+		}
+		print find(3);
+		`, "3\n"},
+		{`
+		fun f() {
+			for (var i = 0; i < 10; i++) {
+				if (i == 2) {
+					return i;
+				}
+			}
+			return nil;
+		}
+		print f();
+		`, "2\n"},
+		{`
+		fun f() {
+			return nil;
+			print "unreachable";
+		}
+		print f();
+		`, "<nil>\n"},
+		{`
+		fun f() {
+			return;
+			print "unreachable";
+		}
+		f();
+		`, ""},
+		{`
+		fun f() {
+			1 + 1;
+		}
+		print f();
+		`, "<nil>\n"},
+		{`
+		fun f(n) {
+			if (n) "then"; else "else";
+		}
+		print f(true);
+		`, "<nil>\n"},
+	}
+
+	for i, tt := range tests {
+		var output bytes.Buffer
+
+		interpreter := New()
+		interpreter.Stdout = &output
+
+		err := interpret(t, interpreter, tt.source)
+		if err != nil {
+			t.Fatalf("tests[%d] - unexpected error: %v", i, err)
+		}
+
+		if output.String() != tt.expected {
+			t.Fatalf("tests[%d] - output wrong. expected=%q, got=%q", i, tt.expected, output.String())
+		}
+	}
+}
+
+func TestIncrement(t *testing.T) {
+	tests := []struct {
+		source        string
+		expected      string
+		expectedError bool
+	}{
+		// postfix operators return the old value,
+		// prefix operators the new one.
+		{"var i = 1; print i++; print i;", "1\n2\n", false},
+		{"var i = 1; print i--; print i;", "1\n0\n", false},
+		{"var i = 1; print ++i; print i;", "2\n2\n", false},
+		{"var i = 1; print --i; print i;", "0\n0\n", false},
+		{"var i = 1.5; i++; print i;", "2.5\n", false},
+		{"var i = 9223372036854775807; i++; print i;", "9223372036854775808\n", false},
+
+		{"var i = 10; i += 2; i -= 3; i *= 2; print i;", "18\n", false},
+		{"var i = 9; i /= 2; print i;", "4.5\n", false},
+		{"var i = 7; i %= 4; print i;", "3\n", false},
+		{`var s = "a"; s += "b"; print s;`, "ab\n", false},
+		{"var i = 1; print i += 2;", "3\n", false},
+
+		// the target is read once, before the value.
+		{`
+		var x = 1;
+		var reads = 0;
+		fun g() { reads++; x = 10; return 1; }
+		x += g();
+		print x;
+		print reads;
+		`, "2\n1\n", false},
+
+		{`var s = "a"; s++;`, "", true},
+		{"const c = 1; c++;", "", true},
+		{"const c = 1; c += 1;", "", true},
+		{"undefined++;", "", true},
+	}
+
+	for i, tt := range tests {
+		var output bytes.Buffer
+
+		interpreter := New()
+		interpreter.Stdout = &output
+
+		err := interpret(t, interpreter, tt.source)
+		if (err != nil) != tt.expectedError {
+			t.Fatalf("tests[%d] - error wrong for %v. expected=%v, got=%v", i, tt.source, tt.expectedError, err)
+		}
+
+		if output.String() != tt.expected {
+			t.Fatalf("tests[%d] - output wrong for %v. expected=%q, got=%q", i, tt.source, tt.expected, output.String())
+		}
+	}
+}
+
+func TestConditional(t *testing.T) {
+	tests := []struct {
+		source   string
+		expected string
+	}{
+		// ?: is right-associative.
+		{"print true ? 1 : false ? 2 : 3;", "1\n"},
+		{"print false ? 1 : true ? 2 : 3;", "2\n"},
+		{"print false ? 1 : false ? 2 : 3;", "3\n"},
+		{`print nil or true ? "a" : "b";`, "a\n"},
+
+		// ?? only replaces nil.
+		{"print nil ?? 1;", "1\n"},
+		{"print false ?? 1;", "false\n"},
+		{"print 0 ?? 1;", "0\n"},
+		{`print ("" ?? 1) == "";`, "true\n"},
+		{"print nil ?? nil ?? 3;", "3\n"},
+
+		// only the operands that are needed are evaluated.
+		{`
+		var calls = 0;
+		fun f(value) { calls++; return value; }
+		print true ? f(1) : f(2);
+		print false ? f(1) : f(2);
+		print calls;
+		`, "1\n2\n2\n"},
+		{`
+		var calls = 0;
+		fun f(value) { calls++; return value; }
+		print 1 ?? f(2);
+		print false ?? f(2);
+		print nil ?? f(2);
+		print calls;
+		`, "1\nfalse\n2\n1\n"},
+	}
+
+	for i, tt := range tests {
+		var output bytes.Buffer
+
+		interpreter := New()
+		interpreter.Stdout = &output
+
+		err := interpret(t, interpreter, tt.source)
+		if err != nil {
+			t.Fatalf("tests[%d] - unexpected error: %v", i, err)
+		}
+
+		if output.String() != tt.expected {
+			t.Fatalf("tests[%d] - output wrong. expected=%q, got=%q", i, tt.expected, output.String())
+		}
+	}
+}
+
+// TestConstants runs without the resolver, to check
+// that the interpreter rejects constant reassignments
+// by itself.
+func TestConstants(t *testing.T) {
+	tests := []struct {
+		source        string
+		expected      string
+		expectedError string
+	}{
+		{"const c = 1; print c;", "1\n", ""},
+		{"const c = 1; { var c = 2; c = 3; print c; } print c;", "3\n1\n", ""},
+		{"const c = 1; c = 2;", "", "Cannot assign to constant c."},
+		{"const c = 1; c += 2;", "", "Cannot assign to constant c."},
+		{"const c = 1; c++;", "", "Cannot assign to constant c."},
+		{"const c = 1; --c;", "", "Cannot assign to constant c."},
+		{"const c = 1; fun f() { c = 2; } f();", "", "Cannot assign to constant c."},
+		{"const c = 1; var c = 2;", "", "Cannot redeclare constant c."},
+		{"const c = 1; const c = 2;", "", "Cannot redeclare constant c."},
+		{"const c = 1; print c; c = 2; print c;", "1\n", "Cannot assign to constant c."},
+	}
+
+	for i, tt := range tests {
+		var output bytes.Buffer
+
+		interpreter := New()
+		interpreter.Stdout = &output
+
+		err := interpret(t, interpreter, tt.source)
+		if tt.expectedError == "" && err != nil || tt.expectedError != "" && (err == nil || err.Error() != tt.expectedError) {
+			t.Fatalf("tests[%d] - error wrong for %v. expected=%q, got=%v", i, tt.source, tt.expectedError, err)
+		}
+
+		if output.String() != tt.expected {
+			t.Fatalf("tests[%d] - output wrong for %v. expected=%q, got=%q", i, tt.source, tt.expected, output.String())
+		}
+	}
+}
@@ -0,0 +1,205 @@
+package interpreter
+
+import (
+	"errors"
+	"fmt"
+	"golox/statement"
+)
+
+// The values for-in loops iterate over are:
+//
+//	strings     their characters
+//	arrays      their elements
+//	maps        their keys, in insertion order
+//	ranges      their numbers
+//	generators  the values they yield
+//	channels    the values received until they are closed
+//	functions   taking no argument, the values they return
+//	            until they return nil
+
+// iterator returns the next value of an iteration,
+// or false once the iteration is over.
+type iterator func() (value any, ok bool, err error)
+
+// Range is a sequence of numbers from Start up to
+// Stop excluded, separated by Step.
+type Range struct {
+	Start any
+	Stop  any
+	Step  any
+}
+
+func (r *Range) String() string {
+	return fmt.Sprintf("range(%v, %v, %v)", r.Start, r.Stop, r.Step)
+}
+
+// iterate returns an iterator over the values of iterable.
+func (i *Interpreter) iterate(iterable any) (iterator, error) {
+	switch v := iterable.(type) {
+	case string:
+		runes := []rune(v)
+		n := 0
+		return func() (any, bool, error) {
+			if n >= len(runes) {
+				return nil, false, nil
+			}
+
+			n++
+			return string(runes[n-1]), true, nil
+		}, nil
+	case *Array:
+		n := 0
+		return func() (any, bool, error) {
+			if n >= len(v.Elements) {
+				return nil, false, nil
+			}
+
+			n++
+			return v.Elements[n-1], true, nil
+		}, nil
+	case *Map:
+		keys := append([]string(nil), v.Keys()...)
+		n := 0
+		return func() (any, bool, error) {
+			if n >= len(keys) {
+				return nil, false, nil
+			}
+
+			n++
+			return keys[n-1], true, nil
+		}, nil
+	case *Range:
+		// the sign of the step tells which way the range goes,
+		// the range going on while the sign of current - stop
+		// is the opposite.
+		direction, _ := compareNumbers(v.Step, int64(0))
+		current := v.Start
+		return func() (any, bool, error) {
+			cmp, _ := compareNumbers(current, v.Stop)
+			if cmp != -direction {
+				return nil, false, nil
+			}
+
+			value := current
+			current = addNumbers(current, v.Step)
+			return value, true, nil
+		}, nil
+	case *Generator:
+		return func() (any, bool, error) {
+			value, err := v.next(i)
+			return value, err == nil && !v.done, err
+		}, nil
+	case *Channel:
+		return func() (any, bool, error) {
+			var value any
+			var ok bool
+			var err error
+			i.blocking(func() {
+				select {
+				case value, ok = <-v.ch:
+				case <-i.context().Done():
+					err = i.stopped()
+				}
+			})
+
+			return value, ok, err
+		}, nil
+	case GoloxCallable:
+		if min, _ := v.Arity(); min != 0 {
+			return nil, errors.New("Can only iterate over functions without parameters.")
+		}
+
+		return func() (any, bool, error) {
+			value, err := i.call(v, nil)
+			return value, err == nil && value != nil, err
+		}, nil
+	}
+
+	return nil, fmt.Errorf("Cannot iterate over %v.", iterable)
+}
+
+// VisitForInStmt runs the body of a for-in loop for each
+// value of the iterable, each time in a new scope.
+func (i *Interpreter) VisitForInStmt(stmt *statement.ForIn) (any, error) {
+	iterable, err := i.evaluate(stmt.Iterable)
+	if err != nil {
+		return nil, err
+	}
+
+	next, err := i.iterate(iterable)
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		value, ok, err := next()
+		if err != nil {
+			return nil, err
+		}
+
+		if !ok {
+			return nil, nil
+		}
+
+		environment := NewEnvironment(i.Environment)
+		environment.Define(stmt.Name.Lexeme, value)
+
+		jump, err := i.ExecuteBlock([]statement.Stmt{stmt.Body}, environment)
+		if err != nil {
+			return nil, err
+		}
+
+		switch jump.(type) {
+		case returnValue:
+			return jump, nil
+		case breakLoop:
+			return nil, nil
+		}
+	}
+}
+
+// iterationNatives returns the natives
+// creating iterable values.
+func iterationNatives() []*NativeFunction {
+	return []*NativeFunction{
+		{
+			// range(stop), range(start, stop)
+			// or range(start, stop, step).
+			Name:   "range",
+			Params: Variadic,
+			Function: func(interpreter *Interpreter, arguments []any) (any, error) {
+				err := checkArgumentCount("range", arguments, 1, 3)
+				if err != nil {
+					return nil, err
+				}
+
+				for n := range arguments {
+					_, err := numberArgument("range", arguments, n)
+					if err != nil {
+						return nil, err
+					}
+				}
+
+				r := &Range{
+					Start: int64(0),
+					Stop:  arguments[0],
+					Step:  int64(1),
+				}
+
+				if len(arguments) >= 2 {
+					r.Start, r.Stop = arguments[0], arguments[1]
+				}
+
+				if len(arguments) == 3 {
+					r.Step = arguments[2]
+				}
+
+				if cmp, ok := compareNumbers(r.Step, int64(0)); !ok || cmp == 0 {
+					return nil, errors.New("range: step must not be zero.")
+				}
+
+				return r, nil
+			},
+		},
+	}
+}
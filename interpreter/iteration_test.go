@@ -0,0 +1,112 @@
+package interpreter
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestForIn(t *testing.T) {
+	tests := []struct {
+		source        string
+		expected      string
+		expectedError bool
+	}{
+		{`for (c in "héllo") write(c + " ");`, "h é l l o ", false},
+		{`for (var x in array(1, 2, 3)) write(x);`, "123", false},
+		{`
+		var m = map();
+		set(m, "b", 1);
+		set(m, "a", 2);
+		for (k in m) write(k);
+		`, "ba", false},
+		{`for (n in range(3)) write(n);`, "012", false},
+		{`for (n in range(5, 0, -2)) write(n);`, "531", false},
+		{`for (n in range(0, 1, 0.5)) printf("{} ", n);`, "0 0.5 ", false},
+		{`for (n in range(3, 3)) write(n);`, "", false},
+		{`
+		fun count(n) {
+			for (var i = 0; i < n; i++) yield i;
+		}
+		for (n in count(3)) write(n);
+		`, "012", false},
+		{`
+		var c = chan();
+		fun produce() {
+			send(c, 1);
+			send(c, 2);
+			close(c);
+		}
+		spawn produce();
+		for (v in c) write(v);
+		`, "12", false},
+		{`
+		var n = 0;
+		fun counter() {
+			if (n == 3) return nil;
+			return n++;
+		}
+		for (v in counter) write(v);
+		`, "012", false},
+		{`
+		for (n in range(10)) {
+			if (n % 2 == 0) continue;
+			if (n > 6) break;
+			write(n);
+		}
+		`, "135", false},
+		{`
+		var i = 0;
+		while (true) {
+			i++;
+			if (i < 3) continue;
+			break;
+		}
+		write(i);
+		`, "3", false},
+		{`
+		for (var i = 0; i < 5; i++) {
+			if (i == 1) continue;
+			write(i);
+		}
+		`, "0234", false},
+		{`
+		fun find(items, item) {
+			for (var i = 0; i < len(items); i++) {
+				for (x in items) {
+					if (x == item) return i;
+				}
+			}
+			return nil;
+		}
+		write(find(array(1, 2), 2));
+		`, "0", false},
+		{`
+		fun first(s) {
+			while (true) {
+				for (c in s) return c;
+			}
+		}
+		write(first("xyz"));
+		`, "x", false},
+		{`fun f() {} write(f());`, "<nil>", false},
+		{`for (n in 3) write(n);`, "", true},
+		{`range(0, 1, 0);`, "", true},
+		{`fun f(a) {} for (x in f) {}`, "", true},
+	}
+
+	for i, tt := range tests {
+		var output bytes.Buffer
+
+		interpreter := New()
+		interpreter.Stdout = &output
+
+		err := interpret(t, interpreter, tt.source)
+		if (err != nil) != tt.expectedError {
+			t.Fatalf("tests[%d] - error wrong. expected=%v, got=%v", i, tt.expectedError, err)
+		}
+
+		if output.String() != tt.expected {
+			t.Fatalf("tests[%d] - output wrong. expected=%q, got=%q", i, tt.expected, output.String())
+		}
+	}
+}
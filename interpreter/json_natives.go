@@ -0,0 +1,322 @@
+package interpreter
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// JSON objects are decoded into maps and JSON arrays into
+// arrays. Numbers without a fraction or exponent become
+// integers, other numbers become floats.
+
+// jsonRangeError is returned for JSON numbers
+// too large to be represented by a float.
+type jsonRangeError struct {
+	number string
+	offset int64
+}
+
+func (e *jsonRangeError) Error() string {
+	return fmt.Sprintf("number %v out of range", e.number)
+}
+
+// decodeJSON decodes the next JSON value from dec.
+func decodeJSON(dec *json.Decoder) (any, error) {
+	tok, err := dec.Token()
+	if err != nil {
+		return nil, err
+	}
+
+	switch t := tok.(type) {
+	case json.Delim:
+		if t == '[' {
+			array := &Array{}
+			for dec.More() {
+				element, err := decodeJSON(dec)
+				if err != nil {
+					return nil, err
+				}
+
+				array.Elements = append(array.Elements, element)
+			}
+
+			_, err := dec.Token()
+			return array, err
+		}
+
+		if t == '{' {
+			object := NewMap()
+			for dec.More() {
+				key, err := dec.Token()
+				if err != nil {
+					return nil, err
+				}
+
+				value, err := decodeJSON(dec)
+				if err != nil {
+					return nil, err
+				}
+
+				object.Set(key.(string), value)
+			}
+
+			_, err := dec.Token()
+			return object, err
+		}
+
+		return nil, fmt.Errorf("unexpected %v", t)
+	case json.Number:
+		// the number is valid JSON, so it can only
+		// fail to parse by being out of range.
+		n := parseNumber(t.String())
+		if n == nil {
+			return nil, &jsonRangeError{
+				number: t.String(),
+				offset: dec.InputOffset() - int64(len(t)),
+			}
+		}
+
+		return n, nil
+	}
+
+	return tok, nil
+}
+
+// sizeOfJSON returns the number of bytes charged for a
+// parsed JSON value, counting the values it contains.
+func sizeOfJSON(value any) uint64 {
+	size := sizeOf(value)
+	switch v := value.(type) {
+	case *Array:
+		for _, element := range v.Elements {
+			size += sizeOfJSON(element)
+		}
+	case *Map:
+		for key, element := range v.values {
+			size += uint64(len(key)) + sizeOfJSON(element)
+		}
+	}
+
+	return size
+}
+
+// parseJSON parses a JSON document. Errors
+// include the offset at which parsing failed.
+func parseJSON(s string) (any, error) {
+	dec := json.NewDecoder(strings.NewReader(s))
+	dec.UseNumber()
+
+	value, err := decodeJSON(dec)
+	if err == nil {
+		_, err = dec.Token()
+		if err == io.EOF {
+			return value, nil
+		}
+
+		if err == nil {
+			err = errors.New("unexpected data after JSON value")
+		}
+	}
+
+	if err == io.EOF {
+		err = errors.New("unexpected end of JSON input")
+	}
+
+	offset := dec.InputOffset()
+
+	var syntaxError *json.SyntaxError
+	if errors.As(err, &syntaxError) {
+		offset = syntaxError.Offset
+	}
+
+	var rangeError *jsonRangeError
+	if errors.As(err, &rangeError) {
+		offset = rangeError.offset
+	}
+
+	return nil, fmt.Errorf("%v at offset %v", err, offset)
+}
+
+// quoteJSON quotes a string as a JSON string.
+func quoteJSON(s string) string {
+	var buf bytes.Buffer
+
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	enc.Encode(s)
+
+	return strings.TrimSuffix(buf.String(), "\n")
+}
+
+// encodeJSON writes the compact JSON encoding of value
+// to buf. seen contains the arrays and maps being encoded
+// to detect structures that contain themselves, and depth
+// is the number of arrays and maps around value.
+func encodeJSON(buf *bytes.Buffer, value any, seen map[any]bool, depth int) error {
+	switch v := value.(type) {
+	case nil:
+		buf.WriteString("null")
+	case bool:
+		buf.WriteString(strconv.FormatBool(v))
+	case int64:
+		buf.WriteString(strconv.FormatInt(v, 10))
+	case *big.Int:
+		buf.WriteString(v.String())
+	case float64:
+		if math.IsNaN(v) || math.IsInf(v, 0) {
+			return fmt.Errorf("cannot convert %v to JSON", v)
+		}
+
+		buf.WriteString(strconv.FormatFloat(v, 'g', -1, 64))
+	case string:
+		buf.WriteString(quoteJSON(v))
+	case *Array:
+		if seen[v] {
+			return errors.New("cannot convert cyclic structure to JSON")
+		}
+
+		if depth >= maxDepth {
+			return errDepth
+		}
+
+		seen[v] = true
+		defer delete(seen, v)
+
+		buf.WriteString("[")
+		for n, element := range v.Elements {
+			if n > 0 {
+				buf.WriteString(",")
+			}
+
+			err := encodeJSON(buf, element, seen, depth+1)
+			if err != nil {
+				return err
+			}
+		}
+		buf.WriteString("]")
+	case *Map:
+		if seen[v] {
+			return errors.New("cannot convert cyclic structure to JSON")
+		}
+
+		if depth >= maxDepth {
+			return errDepth
+		}
+
+		seen[v] = true
+		defer delete(seen, v)
+
+		buf.WriteString("{")
+		for n, key := range v.Keys() {
+			if n > 0 {
+				buf.WriteString(",")
+			}
+
+			buf.WriteString(quoteJSON(key))
+			buf.WriteString(":")
+
+			element, _ := v.Get(key)
+			err := encodeJSON(buf, element, seen, depth+1)
+			if err != nil {
+				return err
+			}
+		}
+		buf.WriteString("}")
+	default:
+		return fmt.Errorf("cannot convert %v to JSON", v)
+	}
+
+	return nil
+}
+
+// maxIndent is the largest number of spaces
+// jsonStringify indents with.
+const maxIndent = 10
+
+// jsonNatives returns the natives converting
+// values from and to JSON.
+func jsonNatives() []*NativeFunction {
+	return []*NativeFunction{
+		{
+			Name:   "jsonParse",
+			Params: 1,
+			Function: func(interpreter *Interpreter, arguments []any) (any, error) {
+				s, err := stringArgument("jsonParse", arguments, 0)
+				if err != nil {
+					return nil, err
+				}
+
+				value, err := parseJSON(s)
+				if err != nil {
+					return nil, fmt.Errorf("jsonParse: %v.", err)
+				}
+
+				err = interpreter.charge(sizeOfJSON(value))
+				if err != nil {
+					return nil, err
+				}
+
+				return value, nil
+			},
+		},
+		{
+			Name:   "jsonStringify",
+			Params: Variadic,
+			Function: func(interpreter *Interpreter, arguments []any) (any, error) {
+				err := checkArgumentCount("jsonStringify", arguments, 1, 2)
+				if err != nil {
+					return nil, err
+				}
+
+				// the indent is either a number of spaces
+				// or the string used to indent.
+				indent := ""
+				if len(arguments) == 2 {
+					switch v := arguments[1].(type) {
+					case nil:
+					case string:
+						indent = v
+					default:
+						spaces, err := intArgument("jsonStringify", arguments, 1)
+						if err != nil || spaces < 0 {
+							return nil, errors.New("jsonStringify: argument 2 must be a string or a non-negative integer.")
+						}
+
+						// as in JavaScript, indents are
+						// at most 10 spaces wide.
+						if spaces > maxIndent {
+							spaces = maxIndent
+						}
+
+						indent = strings.Repeat(" ", spaces)
+					}
+				}
+
+				var buf bytes.Buffer
+				err = encodeJSON(&buf, arguments[0], make(map[any]bool), 0)
+				if err != nil {
+					return nil, fmt.Errorf("jsonStringify: %v.", err)
+				}
+
+				if indent == "" {
+					return interpreter.allocate(buf.String())
+				}
+
+				var indented bytes.Buffer
+				err = json.Indent(&indented, buf.Bytes(), "", indent)
+				if err != nil {
+					return nil, fmt.Errorf("jsonStringify: %v.", err)
+				}
+
+				return interpreter.allocate(indented.String())
+			},
+		},
+	}
+}
@@ -0,0 +1,60 @@
+package interpreter
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestJSON(t *testing.T) {
+	tests := []struct {
+		source        string
+		expected      string
+		expectedError string
+	}{
+		{`print jsonStringify(jsonParse("[1, 2.5, true, null, [], {}]"));`, "[1,2.5,true,null,[],{}]\n", ""},
+		{`var m = map(); set(m, "a", array("x", 1)); print jsonStringify(m);`, "{\"a\":[\"x\",1]}\n", ""},
+		{`print jsonParse("123456789012345678901234567890") + 1;`, "123456789012345678901234567891\n", ""},
+		{`print jsonParse("1e-400");`, "0\n", ""},
+		{`print jsonStringify(array(1, 2), 2);`, "[\n  1,\n  2\n]\n", ""},
+		{`print jsonStringify(array(1), "--");`, "[\n--1\n]\n", ""},
+		{`print jsonStringify(array(1), 4611686018427387904);`, "[\n          1\n]\n", ""},
+
+		// parse errors include the offset.
+		{`jsonParse("[1, 2");`, "", "jsonParse: unexpected end of JSON input at offset 5."},
+		{`jsonParse("[1, 1e400]");`, "", "jsonParse: number 1e400 out of range at offset 4."},
+		{`jsonParse("[1] 2");`, "", "jsonParse: unexpected data after JSON value at offset 5."},
+		{`jsonParse("[1, x]");`, "", "at offset 5."},
+
+		// structures containing themselves.
+		{`var a = array(); push(a, a); jsonStringify(a);`, "", "jsonStringify: cannot convert cyclic structure to JSON."},
+		{`var m = map(); set(m, "m", array(m)); jsonStringify(m);`, "", "jsonStringify: cannot convert cyclic structure to JSON."},
+		{`var a = array(); print jsonStringify(array(a, a));`, "[[],[]]\n", ""},
+
+		// values nested too deeply are errors
+		// rather than overflowing the stack.
+		{nested(maxDepth) + "print len(jsonStringify(a));", fmt.Sprintf("%v\n", 2*maxDepth), ""},
+		{nested(maxDepth+1) + "jsonStringify(a);", "", "jsonStringify: value nested too deeply."},
+		{nested(maxDepth+1) + "var m = map(); set(m, \"a\", a); jsonStringify(m);", "", "jsonStringify: value nested too deeply."},
+
+		{`jsonStringify(math.nan);`, "", "jsonStringify: cannot convert NaN to JSON."},
+		{`jsonStringify(1, -1);`, "", "jsonStringify: argument 2 must be a string or a non-negative integer."},
+	}
+
+	for i, tt := range tests {
+		var output bytes.Buffer
+
+		interpreter := New()
+		interpreter.Stdout = &output
+
+		err := interpret(t, interpreter, tt.source)
+		if tt.expectedError == "" && err != nil || tt.expectedError != "" && (err == nil || !strings.Contains(err.Error(), tt.expectedError)) {
+			t.Fatalf("tests[%d] - error wrong for %v. expected=%q, got=%v", i, tt.source, tt.expectedError, err)
+		}
+
+		if output.String() != tt.expected {
+			t.Fatalf("tests[%d] - output wrong for %v. expected=%q, got=%q", i, tt.source, tt.expected, output.String())
+		}
+	}
+}
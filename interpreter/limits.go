@@ -0,0 +1,156 @@
+package interpreter
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+)
+
+// The errors returned when a script exceeds a limit,
+// which embedders can test with errors.Is. A script
+// stopped by its context returns an error wrapping
+// the error of the context instead.
+var (
+	ErrStepLimit   = errors.New("Step limit exceeded.")
+	ErrCallDepth   = errors.New("Maximum call depth exceeded.")
+	ErrMemoryLimit = errors.New("Memory limit exceeded.")
+)
+
+// DefaultCallDepth is the call depth limit of new
+// interpreters, which stops runaway recursion well
+// before it overflows the Go stack.
+const DefaultCallDepth = 10000
+
+// The number of bytes charged for the elements of arrays
+// and the entries of maps, close to what Go allocates.
+const (
+	elementSize = 16
+	entrySize   = 64
+)
+
+// Limits restricts the resources a script can use.
+// A limit is disabled when it is zero.
+type Limits struct {
+	// Steps is the number of statements and
+	// expressions the script can evaluate.
+	Steps int64
+
+	// CallDepth is the number of nested calls.
+	CallDepth int
+
+	// Memory is the number of bytes the script can
+	// allocate. The script is charged for the strings,
+	// arrays, maps and big integers it creates, including
+	// the ones that are no longer used, so the limit
+	// bounds the work of the script rather than the
+	// memory it holds. The memory used by the interpreter
+	// itself is not counted.
+	Memory uint64
+}
+
+// usage is the resources used by a script. It is
+// shared with the modules the script imports.
+type usage struct {
+	steps int64
+
+	// allocated is the number of bytes
+	// allocated by the script.
+	allocated uint64
+}
+
+// sizeOf returns the number of bytes charged for a value,
+// not counting the values contained in arrays and maps.
+func sizeOf(value any) uint64 {
+	switch v := value.(type) {
+	case string:
+		return uint64(len(v))
+	case *big.Int:
+		return uint64(len(v.Bits())) * 8
+	case *Array:
+		return uint64(len(v.Elements)) * elementSize
+	case *Map:
+		return uint64(len(v.keys)) * entrySize
+	}
+
+	return 0
+}
+
+// charge counts size bytes allocated by the
+// script against the memory limit.
+func (i *Interpreter) charge(size uint64) error {
+	i.usage.allocated += size
+
+	if i.Limits.Memory > 0 && i.usage.allocated > i.Limits.Memory {
+		return ErrMemoryLimit
+	}
+
+	return nil
+}
+
+// allocate charges a value created by the script
+// against the memory limit and returns it.
+func (i *Interpreter) allocate(value any) (any, error) {
+	err := i.charge(sizeOf(value))
+	if err != nil {
+		return nil, err
+	}
+
+	return value, nil
+}
+
+// context returns the context of the script, which
+// is done once the run of the script ends.
+func (i *Interpreter) context() context.Context {
+	if i.run != nil {
+		return i.run
+	}
+
+	if i.Context == nil {
+		return context.Background()
+	}
+
+	return i.Context
+}
+
+// stopped returns the error of a script stopped by its context.
+func (i *Interpreter) stopped() error {
+	return fmt.Errorf("Execution stopped: %w", i.context().Err())
+}
+
+// step counts a statement or an expression about
+// to be evaluated against the limits.
+func (i *Interpreter) step() error {
+	select {
+	case <-i.context().Done():
+		return i.stopped()
+	default:
+	}
+
+	i.usage.steps++
+
+	if i.usage.steps%yieldInterval == 0 {
+		i.scheduler.yield()
+	}
+
+	if i.Limits.Steps > 0 && i.usage.steps > i.Limits.Steps {
+		return ErrStepLimit
+	}
+
+	return nil
+}
+
+// call calls a callable, counting the call
+// against the call depth limit.
+func (i *Interpreter) call(function GoloxCallable, arguments []any) (any, error) {
+	if i.Limits.CallDepth > 0 && i.depth >= i.Limits.CallDepth {
+		return nil, ErrCallDepth
+	}
+
+	i.depth++
+	defer func() {
+		i.depth--
+	}()
+
+	return function.Call(i, arguments)
+}
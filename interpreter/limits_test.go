@@ -0,0 +1,145 @@
+package interpreter
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"golox/parser"
+	"golox/scanner"
+	"io"
+	"testing"
+	"time"
+)
+
+// interpret runs a golox source with interpreter.
+func interpret(t *testing.T, interpreter *Interpreter, source string) error {
+	t.Helper()
+
+	scanner := scanner.New(source)
+	tokens := scanner.ScanTokens()
+	if scanner.HadError {
+		t.Fatalf("cannot scan %q", source)
+	}
+
+	parser := parser.Parser{
+		Tokens: tokens,
+	}
+	statements, isError := parser.Parse()
+	if isError {
+		t.Fatalf("cannot parse %q", source)
+	}
+
+	return interpreter.Interpret(statements)
+}
+
+func TestLimits(t *testing.T) {
+	expired, cancel := context.WithTimeout(context.Background(), time.Millisecond)
+	defer cancel()
+
+	tests := []struct {
+		source        string
+		limits        Limits
+		ctx           context.Context
+		expectedError error
+	}{
+		{"while (true) {}", Limits{Steps: 1000}, nil, ErrStepLimit},
+		{"var i = 0; while (i < 10) i = i + 1;", Limits{Steps: 1000}, nil, nil},
+		{"fun f(n) { return f(n + 1); } f(0);", Limits{CallDepth: 100}, nil, ErrCallDepth},
+		{"fun f(n) { if (n > 0) f(n - 1); } f(99);", Limits{CallDepth: 100}, nil, nil},
+		{`var s = ""; while (true) s = s + "xxxxxxxx";`, Limits{Memory: 1 << 20}, nil, ErrMemoryLimit},
+		{"while (true) {}", Limits{}, expired, context.DeadlineExceeded},
+		{"time.sleep(10);", Limits{}, expired, context.DeadlineExceeded},
+	}
+
+	for i, tt := range tests {
+		interpreter := New()
+		interpreter.Limits = tt.limits
+		interpreter.Context = tt.ctx
+
+		err := interpret(t, interpreter, tt.source)
+		if !errors.Is(err, tt.expectedError) {
+			t.Fatalf("tests[%d] - error wrong for %q. expected=%v, got=%v", i, tt.source, tt.expectedError, err)
+		}
+	}
+}
+
+func TestMemoryLimit(t *testing.T) {
+	tests := []struct {
+		source        string
+		expectedError error
+	}{
+		{`var a = array(); while (true) push(a, 1);`, ErrMemoryLimit},
+		{`var m = map(); var i = 0; while (true) { set(m, format("{}", i), i); i++; }`, ErrMemoryLimit},
+		{`var n = 3; while (true) n = n * n;`, ErrMemoryLimit},
+		{`var s = "x"; while (true) s = repeat(s, 2);`, ErrMemoryLimit},
+		{`while (true) jsonParse("[1, 2, 3, 4, 5, 6, 7, 8]");`, ErrMemoryLimit},
+		{`fun f(...rest) {} while (true) f(1, 2, 3);`, ErrMemoryLimit},
+		{`var s = repeat("x", 1000); while (true) reSplit("", s);`, ErrMemoryLimit},
+
+		// numbers and values that are not copied
+		// do not count against the limit.
+		{`var i = 0; while (i < 100000) i = i + 1;`, nil},
+		{`var m = map(); var i = 0; while (i < 100000) { set(m, "k", i); i++; }`, nil},
+		{`var a = array(repeat("x", 1000)); var i = 0; while (i < 100000) { get(a, 0); i++; }`, nil},
+	}
+
+	for i, tt := range tests {
+		interpreter := New()
+		interpreter.Limits = Limits{Memory: 1 << 20}
+
+		err := interpret(t, interpreter, tt.source)
+		if !errors.Is(err, tt.expectedError) {
+			t.Fatalf("tests[%d] - error wrong for %q. expected=%v, got=%v", i, tt.source, tt.expectedError, err)
+		}
+	}
+}
+
+func TestPoolMemoryLimit(t *testing.T) {
+	hungry, err := Compile(`var a = array(); while (true) push(a, 1);`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	frugal, err := Compile(`var s = ""; var i = 0; while (i < 100) { s = s + "x"; i++; } print len(s);`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	pool := NewPool(8, func(interpreter *Interpreter) {
+		interpreter.Limits.Memory = 1 << 20
+	})
+
+	// the allocations of the hungry scripts are
+	// not charged to the scripts next to them.
+	errs := make(chan error, 8)
+	for n := 0; n < 4; n++ {
+		go func() {
+			errs <- pool.Run(context.Background(), hungry, io.Discard)
+		}()
+		go func() {
+			var output bytes.Buffer
+			err := pool.Run(context.Background(), frugal, &output)
+			if err == nil && output.String() != "100\n" {
+				err = fmt.Errorf("output wrong. expected=%q, got=%q", "100\n", output.String())
+			}
+
+			errs <- err
+		}()
+	}
+
+	hungryErrors := 0
+	for n := 0; n < 8; n++ {
+		err := <-errs
+		switch {
+		case errors.Is(err, ErrMemoryLimit):
+			hungryErrors++
+		case err != nil:
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	if hungryErrors != 4 {
+		t.Fatalf("memory limit exceeded %v times. expected=4", hungryErrors)
+	}
+}
@@ -0,0 +1,224 @@
+package interpreter
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+)
+
+// mathFunction creates a native taking a float and
+// returning a float from a function of the math package.
+func mathFunction(name string, f func(float64) float64) *NativeFunction {
+	return &NativeFunction{
+		Name:   name,
+		Params: 1,
+		Function: func(interpreter *Interpreter, arguments []any) (any, error) {
+			x, err := floatArgument(name, arguments, 0)
+			if err != nil {
+				return nil, err
+			}
+
+			return f(x), nil
+		},
+	}
+}
+
+// roundingFunction creates a native rounding a number
+// to an integer. Integers are returned unchanged, and
+// so are infinities and NaN which have no integer value.
+func roundingFunction(name string, f func(float64) float64) *NativeFunction {
+	return &NativeFunction{
+		Name:   name,
+		Params: 1,
+		Function: func(interpreter *Interpreter, arguments []any) (any, error) {
+			x, err := numberArgument(name, arguments, 0)
+			if err != nil {
+				return nil, err
+			}
+
+			if isInteger(x) {
+				return x, nil
+			}
+
+			rounded := f(x.(float64))
+			if n, ok := toInteger(rounded); ok {
+				return normalizeInt(n), nil
+			}
+
+			return rounded, nil
+		},
+	}
+}
+
+// extremum creates a native returning the number that
+// compares first according to want, which is -1 for the
+// minimum and 1 for the maximum. NaN arguments make the
+// result NaN.
+func extremum(name string, want int) *NativeFunction {
+	return &NativeFunction{
+		Name:   name,
+		Params: Variadic,
+		Function: func(interpreter *Interpreter, arguments []any) (any, error) {
+			if len(arguments) == 0 {
+				return nil, fmt.Errorf("%v: expected at least 1 argument.", name)
+			}
+
+			var result any
+			for n := range arguments {
+				x, err := numberArgument(name, arguments, n)
+				if err != nil {
+					return nil, err
+				}
+
+				if result == nil {
+					result = x
+					continue
+				}
+
+				cmp, ok := compareNumbers(x, result)
+				if !ok {
+					return math.NaN(), nil
+				}
+
+				if cmp == want {
+					result = x
+				}
+			}
+
+			return result, nil
+		},
+	}
+}
+
+// newMathModule creates the math namespace.
+func newMathModule() *Module {
+	return newNamespace("math", map[string]any{
+		"pi":  math.Pi,
+		"e":   math.E,
+		"inf": math.Inf(1),
+		"nan": math.NaN(),
+
+		"sqrt":  mathFunction("sqrt", math.Sqrt),
+		"sin":   mathFunction("sin", math.Sin),
+		"cos":   mathFunction("cos", math.Cos),
+		"tan":   mathFunction("tan", math.Tan),
+		"asin":  mathFunction("asin", math.Asin),
+		"acos":  mathFunction("acos", math.Acos),
+		"atan":  mathFunction("atan", math.Atan),
+		"exp":   mathFunction("exp", math.Exp),
+		"log":   mathFunction("log", math.Log),
+		"log2":  mathFunction("log2", math.Log2),
+		"log10": mathFunction("log10", math.Log10),
+
+		"floor": roundingFunction("floor", math.Floor),
+		"ceil":  roundingFunction("ceil", math.Ceil),
+		"round": roundingFunction("round", math.Round),
+
+		"min": extremum("min", -1),
+		"max": extremum("max", 1),
+
+		"atan2": &NativeFunction{
+			Name:   "atan2",
+			Params: 2,
+			Function: func(interpreter *Interpreter, arguments []any) (any, error) {
+				y, err := floatArgument("atan2", arguments, 0)
+				if err != nil {
+					return nil, err
+				}
+
+				x, err := floatArgument("atan2", arguments, 1)
+				if err != nil {
+					return nil, err
+				}
+
+				return math.Atan2(y, x), nil
+			},
+		},
+
+		"pow": &NativeFunction{
+			Name:   "pow",
+			Params: 2,
+			Function: func(interpreter *Interpreter, arguments []any) (any, error) {
+				x, err := numberArgument("pow", arguments, 0)
+				if err != nil {
+					return nil, err
+				}
+
+				y, err := numberArgument("pow", arguments, 1)
+				if err != nil {
+					return nil, err
+				}
+
+				return powerNumbers(x, y)
+			},
+		},
+
+		"abs": &NativeFunction{
+			Name:   "abs",
+			Params: 1,
+			Function: func(interpreter *Interpreter, arguments []any) (any, error) {
+				x, err := numberArgument("abs", arguments, 0)
+				if err != nil {
+					return nil, err
+				}
+
+				switch n := x.(type) {
+				case float64:
+					return math.Abs(n), nil
+				case int64:
+					if n < 0 {
+						return negateNumber(n), nil
+					}
+
+					return n, nil
+				}
+
+				return normalizeInt(new(big.Int).Abs(x.(*big.Int))), nil
+			},
+		},
+
+		"isNaN": &NativeFunction{
+			Name:   "isNaN",
+			Params: 1,
+			Function: func(interpreter *Interpreter, arguments []any) (any, error) {
+				x, err := numberArgument("isNaN", arguments, 0)
+				if err != nil {
+					return nil, err
+				}
+
+				f, ok := x.(float64)
+				return ok && math.IsNaN(f), nil
+			},
+		},
+
+		"clamp": &NativeFunction{
+			Name:   "clamp",
+			Params: 3,
+			Function: func(interpreter *Interpreter, arguments []any) (any, error) {
+				for n := range arguments {
+					_, err := numberArgument("clamp", arguments, n)
+					if err != nil {
+						return nil, err
+					}
+				}
+
+				x, lo, hi := arguments[0], arguments[1], arguments[2]
+
+				if cmp, ok := compareNumbers(lo, hi); !ok || cmp > 0 {
+					return nil, errors.New("clamp: lower bound must not be greater than upper bound.")
+				}
+
+				if cmp, ok := compareNumbers(x, lo); ok && cmp < 0 {
+					return lo, nil
+				}
+
+				if cmp, ok := compareNumbers(x, hi); ok && cmp > 0 {
+					return hi, nil
+				}
+
+				return x, nil
+			},
+		},
+	})
+}
@@ -0,0 +1,64 @@
+package interpreter
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestMath(t *testing.T) {
+	tests := []struct {
+		source        string
+		expected      string
+		expectedError bool
+	}{
+		{"print math.sqrt(16);", "4\n", false},
+		{"print math.floor(2.7);", "2\n", false},
+		{"print math.ceil(-2.7);", "-2\n", false},
+		{"print math.round(2.5);", "3\n", false},
+		{"print math.floor(3);", "3\n", false},
+		{"print math.min(3, 1.5, 2);", "1.5\n", false},
+		{"print math.max(3, 1.5, 2);", "3\n", false},
+		{"print math.clamp(5, 0, 3);", "3\n", false},
+		{"print math.clamp(-1, 0, 3);", "0\n", false},
+		{"print math.atan2(0, 1);", "0\n", false},
+		{"print math.pow(2, 10);", "1024\n", false},
+		{"print math.pow(4, 0.5);", "2\n", false},
+
+		// domain errors give NaN or infinities.
+		{"print math.isNaN(math.sqrt(-1));", "true\n", false},
+		{"print math.isNaN(math.asin(2));", "true\n", false},
+		{"print math.log(0);", "-Inf\n", false},
+		{"print math.floor(math.inf);", "+Inf\n", false},
+		{"print math.isNaN(math.max(1, math.nan));", "true\n", false},
+		{"print math.isNaN(1);", "false\n", false},
+
+		// integers are promoted to big integers.
+		{"print math.abs(-9223372036854775807 - 1);", "9223372036854775808\n", false},
+		{"print math.abs(-5);", "5\n", false},
+		{"print math.pow(2, 64);", "18446744073709551616\n", false},
+		{"print math.floor(1e20);", "100000000000000000000\n", false},
+		{"print math.max(2 ** 70, 1);", "1180591620717411303424\n", false},
+		{"print math.clamp(2 ** 70, 0, 2 ** 64);", "18446744073709551616\n", false},
+
+		{"math.sqrt(\"a\");", "", true},
+		{"math.min();", "", true},
+		{"math.clamp(1, 3, 0);", "", true},
+		{"math.pow(2);", "", true},
+	}
+
+	for i, tt := range tests {
+		var output bytes.Buffer
+
+		interpreter := New()
+		interpreter.Stdout = &output
+
+		err := interpret(t, interpreter, tt.source)
+		if (err != nil) != tt.expectedError {
+			t.Fatalf("tests[%d] - error wrong for %v. expected=%v, got=%v", i, tt.source, tt.expectedError, err)
+		}
+
+		if output.String() != tt.expected {
+			t.Fatalf("tests[%d] - output wrong for %v. expected=%q, got=%q", i, tt.source, tt.expected, output.String())
+		}
+	}
+}
@@ -0,0 +1,287 @@
+package interpreter
+
+import (
+	"fmt"
+	"golox/token"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// Module is a golox module. A module exposes the
+// exported variables of its global environment.
+type Module struct {
+	Name string
+
+	// Values contains the global variables of the module,
+	// which are shared with the module's environment so
+	// that exported variables stay up to date.
+	Values map[string]any
+
+	// Exports contains the names of the exported variables.
+	Exports map[string]bool
+}
+
+// Get returns the value of an exported variable.
+func (m *Module) Get(name token.Token) (any, error) {
+	if m.Exports[name.Lexeme] {
+		return m.Values[name.Lexeme], nil
+	}
+
+	return nil, fmt.Errorf("Module %v has no export %v.", m.Name, name.Lexeme)
+}
+
+func (m *Module) String() string {
+	return fmt.Sprintf("<module %v>", m.Name)
+}
+
+// ModuleLoader loads modules from files. A module is
+// compiled once and cached by its canonical path, but
+// it is executed once by each script importing it, in
+// its own global environment, so that scripts never
+// share the state of a module. A ModuleLoader can be
+// shared by interpreters running on several goroutines,
+// as long as SearchPaths is not modified while they run.
+type ModuleLoader struct {
+	// SearchPaths contains the directories searched for
+	// imports that are not relative to the importing file.
+	SearchPaths []string
+
+	// lock guards programs.
+	lock     sync.Mutex
+	programs map[string]*Program
+}
+
+// moduleEntry is a module that is loaded or being
+// loaded by a script. done is closed once it is loaded.
+type moduleEntry struct {
+	module *Module
+	err    error
+	done   chan struct{}
+}
+
+// NewModuleLoader creates a new ModuleLoader searching
+// the given directories.
+func NewModuleLoader(searchPaths []string) *ModuleLoader {
+	return &ModuleLoader{
+		SearchPaths: searchPaths,
+		programs:    make(map[string]*Program),
+	}
+}
+
+// find resolves an import path to a canonical file path.
+// Paths starting with "./" or "../" are relative to dir,
+// which is the directory of the importing file. Other
+// relative paths are looked up in dir and then in the
+// search paths. The ".golox" extension may be omitted.
+// Modules are files, so importer must be allowed to read
+// them like any other file.
+func (l *ModuleLoader) find(path string, importer *Interpreter) (string, error) {
+	dir := importer.Dir
+
+	var candidates []string
+
+	switch {
+	case filepath.IsAbs(path):
+		candidates = []string{path}
+	case strings.HasPrefix(path, "./") || strings.HasPrefix(path, "../"):
+		candidates = []string{filepath.Join(dir, path)}
+	default:
+		candidates = []string{filepath.Join(dir, path)}
+		for _, searchPath := range l.SearchPaths {
+			candidates = append(candidates, filepath.Join(searchPath, path))
+		}
+	}
+
+	var denied error
+	for _, candidate := range candidates {
+		for _, file := range []string{candidate, candidate + ".golox"} {
+			// the candidates are already relative to dir.
+			abs, err := filepath.Abs(file)
+			if err != nil {
+				return "", err
+			}
+
+			// access is checked before looking at the file,
+			// so that scripts cannot probe for denied files.
+			resolved, err := importer.checkFileAccess("import", abs, false)
+			if err != nil {
+				denied = err
+				continue
+			}
+
+			info, err := os.Stat(resolved)
+			if err != nil || info.IsDir() {
+				continue
+			}
+
+			return resolved, nil
+		}
+	}
+
+	if denied != nil {
+		return "", denied
+	}
+
+	return "", fmt.Errorf("Cannot find module %v.", path)
+}
+
+// Enter marks the script at path as being executed by
+// the interpreter, so that modules importing it are
+// reported as a cycle.
+func (i *Interpreter) Enter(path string) error {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return err
+	}
+
+	file, err := filepath.EvalSymlinks(abs)
+	if err != nil {
+		return err
+	}
+
+	i.imports = []string{file}
+	return nil
+}
+
+// newModule creates an interpreter for a module imported
+// by i. The module has its own global environment but
+// shares the configuration of i.
+func (i *Interpreter) newModule(file string) *Interpreter {
+	module := New()
+	module.Loader = i.Loader
+	module.Dir = filepath.Dir(file)
+	module.Files = i.Files
+	module.Process = i.Process
+	module.env = i.env
+	module.Args = i.Args
+	module.Stdin = i.Stdin
+	module.stdin = i.stdin
+	module.Stdout = i.Stdout
+	module.Random = i.Random
+	module.Context = i.Context
+	module.run = i.run
+	module.Limits = i.Limits
+	module.usage = i.usage
+	module.scheduler = i.scheduler
+	module.modules = i.modules
+	module.imports = append(append([]string{}, i.imports...), file)
+
+	return module
+}
+
+// Load loads the module at path, imported by importer.
+// Imports of a module being loaded by another task of
+// the script wait until it is loaded.
+func (l *ModuleLoader) Load(path string, importer *Interpreter) (*Module, error) {
+	file, err := l.find(path, importer)
+	if err != nil {
+		return nil, err
+	}
+
+	// the modules of a script are only accessed by the
+	// task holding the lock of the scheduler.
+	entry, loaded := importer.modules[file]
+	if loaded {
+		select {
+		case <-entry.done:
+			return entry.module, entry.err
+		default:
+		}
+	}
+
+	// the modules imported along the way from the
+	// script to the importer are still being loaded.
+	for n, loading := range importer.imports {
+		if loading == file {
+			var cycle []string
+			for _, f := range append(importer.imports[n:], file) {
+				cycle = append(cycle, filepath.Base(f))
+			}
+
+			return nil, fmt.Errorf("Import cycle: %v.", strings.Join(cycle, " -> "))
+		}
+	}
+
+	if loaded {
+		importer.blocking(func() {
+			select {
+			case <-entry.done:
+			case <-importer.context().Done():
+				err = importer.stopped()
+			}
+		})
+
+		if err != nil {
+			return nil, err
+		}
+
+		return entry.module, entry.err
+	}
+
+	entry = &moduleEntry{
+		done: make(chan struct{}),
+	}
+	importer.modules[file] = entry
+
+	entry.module, entry.err = l.load(path, file, importer)
+
+	// failed imports are forgotten, so that
+	// they can be imported again.
+	if entry.err != nil {
+		delete(importer.modules, file)
+	}
+
+	close(entry.done)
+	return entry.module, entry.err
+}
+
+// compile reads and compiles the module in file,
+// unless it was already compiled.
+func (l *ModuleLoader) compile(path string, file string) (*Program, error) {
+	l.lock.Lock()
+	program, ok := l.programs[file]
+	l.lock.Unlock()
+
+	if ok {
+		return program, nil
+	}
+
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+
+	program, err = Compile(string(data))
+	if err != nil {
+		return nil, fmt.Errorf("Cannot load module %v.", path)
+	}
+
+	l.lock.Lock()
+	l.programs[file] = program
+	l.lock.Unlock()
+
+	return program, nil
+}
+
+// load executes the module in file.
+func (l *ModuleLoader) load(path string, file string, importer *Interpreter) (*Module, error) {
+	program, err := l.compile(path, file)
+	if err != nil {
+		return nil, err
+	}
+
+	interpreter := importer.newModule(file)
+
+	err = interpreter.interpret(program.Statements)
+	if err != nil {
+		return nil, fmt.Errorf("Error in module %v: %w", path, err)
+	}
+
+	return &Module{
+		Name:    strings.TrimSuffix(filepath.Base(file), ".golox"),
+		Values:  interpreter.Globals.Values,
+		Exports: interpreter.Exports,
+	}, nil
+}
@@ -0,0 +1,212 @@
+package interpreter
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// writeModules writes golox sources in a new directory.
+func writeModules(t *testing.T, sources map[string]string) string {
+	t.Helper()
+
+	dir := t.TempDir()
+	for name, source := range sources {
+		err := os.WriteFile(filepath.Join(dir, name), []byte(source), 0o644)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	return dir
+}
+
+func TestImportCycle(t *testing.T) {
+	dir := writeModules(t, map[string]string{
+		"main.golox": `import "./a" as a;`,
+		"a.golox":    `import "./b" as b;`,
+		"b.golox":    `import "./main" as main;`,
+	})
+
+	interpreter := New()
+	interpreter.Dir = dir
+	interpreter.Files = &FileAccess{}
+	err := interpreter.Enter(filepath.Join(dir, "main.golox"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = interpret(t, interpreter, `import "./a" as a;`)
+	if err == nil || !strings.Contains(err.Error(), "Import cycle: main.golox -> a.golox -> b.golox -> main.golox.") {
+		t.Fatalf("error wrong. got=%v", err)
+	}
+}
+
+func TestImportAccess(t *testing.T) {
+	dir := writeModules(t, map[string]string{
+		"lib.golox": `export const answer = 42;`,
+	})
+
+	tests := []struct {
+		files         *FileAccess
+		expected      string
+		expectedError string
+	}{
+		{&FileAccess{}, "42\n", ""},
+		{&FileAccess{Roots: []string{dir}, ReadOnly: true}, "42\n", ""},
+		{nil, "", "import: file access is disabled."},
+		{&FileAccess{Roots: []string{t.TempDir()}}, "", "import: access to"},
+	}
+
+	for i, tt := range tests {
+		var output bytes.Buffer
+
+		interpreter := New()
+		interpreter.Dir = dir
+		interpreter.Files = tt.files
+		interpreter.Stdout = &output
+
+		err := interpret(t, interpreter, `import { answer } from "./lib"; print answer;`)
+		if tt.expectedError == "" && err != nil || tt.expectedError != "" && (err == nil || !strings.Contains(err.Error(), tt.expectedError)) {
+			t.Fatalf("tests[%d] - error wrong. expected=%q, got=%v", i, tt.expectedError, err)
+		}
+
+		if output.String() != tt.expected {
+			t.Fatalf("tests[%d] - output wrong. expected=%q, got=%q", i, tt.expected, output.String())
+		}
+	}
+}
+
+func TestImportConstants(t *testing.T) {
+	dir := writeModules(t, map[string]string{
+		"lib.golox": `export const k = 1; export var v = 2;`,
+	})
+
+	tests := []struct {
+		source        string
+		expected      string
+		expectedError string
+	}{
+		{`import { k, v } from "./lib"; print k + v;`, "3\n", ""},
+		{`import { k } from "./lib"; k = 3;`, "", "Cannot assign to constant k."},
+		{`import { v } from "./lib"; v = 3;`, "", "Cannot assign to constant v."},
+		{`import "./lib" as lib; lib = 3;`, "", "Cannot assign to constant lib."},
+		{`import { k } from "./lib"; var k = 3;`, "", "Cannot redeclare constant k."},
+	}
+
+	for i, tt := range tests {
+		var output bytes.Buffer
+
+		interpreter := New()
+		interpreter.Dir = dir
+		interpreter.Files = &FileAccess{}
+		interpreter.Stdout = &output
+
+		err := interpret(t, interpreter, tt.source)
+		if tt.expectedError == "" && err != nil || tt.expectedError != "" && (err == nil || err.Error() != tt.expectedError) {
+			t.Fatalf("tests[%d] - error wrong. expected=%q, got=%v", i, tt.expectedError, err)
+		}
+
+		if output.String() != tt.expected {
+			t.Fatalf("tests[%d] - output wrong. expected=%q, got=%q", i, tt.expected, output.String())
+		}
+	}
+}
+
+func TestSharedLoader(t *testing.T) {
+	dir := writeModules(t, map[string]string{
+		"counter.golox": `
+		print "loading";
+		var n = 0;
+		export fun increment() {
+			n += 1;
+			return n;
+		}
+		`,
+	})
+
+	program, err := Compile(`
+	import { increment } from "./counter";
+	import "./counter" as counter;
+	for (var i = 0; i < 100; i++) increment();
+	print counter.increment();
+	`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	loader := NewModuleLoader(nil)
+	pool := NewPool(4, func(interpreter *Interpreter) {
+		interpreter.Loader = loader
+		interpreter.Dir = dir
+		interpreter.Files = &FileAccess{}
+	})
+
+	outputs := make([]bytes.Buffer, 8)
+
+	var wg sync.WaitGroup
+	for n := range outputs {
+		wg.Add(1)
+		go func(output *bytes.Buffer) {
+			defer wg.Done()
+
+			err := pool.Run(context.Background(), program, output)
+			if err != nil {
+				t.Error(err)
+			}
+		}(&outputs[n])
+	}
+	wg.Wait()
+
+	// the module is compiled once, but each program runs
+	// it once and has its own counter.
+	for n := range outputs {
+		if outputs[n].String() != "loading\n101\n" {
+			t.Fatalf("outputs[%d] - output wrong. got=%q", n, outputs[n].String())
+		}
+	}
+
+	if len(loader.programs) != 1 {
+		t.Fatalf("programs wrong. expected=1, got=%v", len(loader.programs))
+	}
+}
+
+func TestConcurrentImports(t *testing.T) {
+	dir := writeModules(t, map[string]string{
+		"config.golox": `
+		print "loading";
+		time.sleep(0.01);
+		export const answer = 42;
+		`,
+	})
+
+	var output bytes.Buffer
+
+	interpreter := New()
+	interpreter.Dir = dir
+	interpreter.Files = &FileAccess{}
+	interpreter.Stdout = &output
+
+	// the second task waits for the module
+	// being loaded by the first one.
+	err := interpret(t, interpreter, `
+	fun load() {
+		import { answer } from "./config";
+		return answer;
+	}
+	var a = spawn load();
+	var b = spawn load();
+	print wait(a) + wait(b);
+	`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if output.String() != "loading\n84\n" {
+		t.Fatalf("output wrong. got=%q", output.String())
+	}
+}
@@ -0,0 +1,125 @@
+package interpreter
+
+import (
+	"fmt"
+)
+
+// Variadic is the arity of natives that
+// accept any number of arguments.
+const Variadic = -1
+
+// NativeFunction is a golox function implemented in Go.
+type NativeFunction struct {
+	Name string
+
+	// Params is the number of parameters of the
+	// function, or Variadic.
+	Params int
+
+	Function func(interpreter *Interpreter, arguments []any) (any, error)
+}
+
+func (n *NativeFunction) Call(
+	interpreter *Interpreter,
+	arguments []any,
+) (any, error) {
+	return n.Function(interpreter, arguments)
+}
+
+func (n *NativeFunction) Arity() (int, int) {
+	if n.Params == Variadic {
+		return 0, Variadic
+	}
+
+	return n.Params, n.Params
+}
+
+// Parameters returns nil, as natives
+// take no named arguments.
+func (n *NativeFunction) Parameters() []string {
+	return nil
+}
+
+func (n *NativeFunction) String() string {
+	return fmt.Sprintf("<native fn %v>", n.Name)
+}
+
+// newNamespace creates a module containing natives
+// and other values defined by the interpreter.
+func newNamespace(name string, values map[string]any) *Module {
+	exports := make(map[string]bool)
+	for name := range values {
+		exports[name] = true
+	}
+
+	return &Module{
+		Name:    name,
+		Values:  values,
+		Exports: exports,
+	}
+}
+
+// numberArgument returns the argument at index n,
+// checking that it is a number.
+func numberArgument(function string, arguments []any, n int) (any, error) {
+	if !isNumber(arguments[n]) {
+		return nil, fmt.Errorf("%v: argument %v must be a number.", function, n+1)
+	}
+
+	return arguments[n], nil
+}
+
+// stringArgument returns the argument at index n,
+// checking that it is a string.
+func stringArgument(function string, arguments []any, n int) (string, error) {
+	s, ok := arguments[n].(string)
+	if !ok {
+		return "", fmt.Errorf("%v: argument %v must be a string.", function, n+1)
+	}
+
+	return s, nil
+}
+
+// intArgument returns the argument at index n as an int,
+// checking that it is an integer small enough to be used
+// as an index or a count.
+func intArgument(function string, arguments []any, n int) (int, error) {
+	v, ok := arguments[n].(int64)
+	if !ok || v != int64(int(v)) {
+		return 0, fmt.Errorf("%v: argument %v must be an integer.", function, n+1)
+	}
+
+	return int(v), nil
+}
+
+// arrayArgument returns the argument at index n,
+// checking that it is an array.
+func arrayArgument(function string, arguments []any, n int) (*Array, error) {
+	a, ok := arguments[n].(*Array)
+	if !ok {
+		return nil, fmt.Errorf("%v: argument %v must be an array.", function, n+1)
+	}
+
+	return a, nil
+}
+
+// checkArgumentCount checks the number of arguments
+// passed to a variadic native.
+func checkArgumentCount(function string, arguments []any, min int, max int) error {
+	if len(arguments) < min || len(arguments) > max {
+		return fmt.Errorf("%v: expected %v to %v arguments but got %v.", function, min, max, len(arguments))
+	}
+
+	return nil
+}
+
+// floatArgument returns the argument at index n
+// as a float, checking that it is a number.
+func floatArgument(function string, arguments []any, n int) (float64, error) {
+	v, err := numberArgument(function, arguments, n)
+	if err != nil {
+		return 0, err
+	}
+
+	return toFloat(v), nil
+}
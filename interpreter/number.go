@@ -0,0 +1,341 @@
+package interpreter
+
+import (
+	"errors"
+	"golox/token"
+	"math"
+	"math/big"
+)
+
+// Golox has two kinds of numbers, floats and integers.
+// Floats are represented as Go's float64. Integers are
+// represented as int64 and are promoted to *big.Int when
+// an operation overflows, so integer arithmetic never
+// loses precision. Results are demoted back to int64
+// whenever they fit.
+
+// isNumber checks if a value is a golox number.
+func isNumber(v any) bool {
+	switch v.(type) {
+	case float64, int64, *big.Int:
+		return true
+	}
+
+	return false
+}
+
+// isInteger checks if a value is a golox integer.
+func isInteger(v any) bool {
+	switch v.(type) {
+	case int64, *big.Int:
+		return true
+	}
+
+	return false
+}
+
+// toBig converts an integer to a *big.Int. The returned
+// value must not be modified as it may be shared.
+func toBig(v any) *big.Int {
+	switch n := v.(type) {
+	case int64:
+		return big.NewInt(n)
+	case *big.Int:
+		return n
+	}
+
+	return nil
+}
+
+// toFloat converts a number to a float64.
+func toFloat(v any) float64 {
+	switch n := v.(type) {
+	case float64:
+		return n
+	case int64:
+		return float64(n)
+	case *big.Int:
+		f, _ := new(big.Float).SetInt(n).Float64()
+		return f
+	}
+
+	return 0
+}
+
+// normalizeInt demotes a *big.Int to an int64
+// if it fits.
+func normalizeInt(n *big.Int) any {
+	if n.IsInt64() {
+		return n.Int64()
+	}
+
+	return n
+}
+
+// addNumbers adds two numbers.
+func addNumbers(a any, b any) any {
+	if x, ok := a.(int64); ok {
+		if y, ok := b.(int64); ok {
+			r := x + y
+			if (x > 0 && y > 0 && r < 0) || (x < 0 && y < 0 && r >= 0) {
+				return new(big.Int).Add(toBig(x), toBig(y))
+			}
+
+			return r
+		}
+	}
+
+	if isInteger(a) && isInteger(b) {
+		return normalizeInt(new(big.Int).Add(toBig(a), toBig(b)))
+	}
+
+	return toFloat(a) + toFloat(b)
+}
+
+// subtractNumbers subtracts b from a.
+func subtractNumbers(a any, b any) any {
+	if x, ok := a.(int64); ok {
+		if y, ok := b.(int64); ok {
+			r := x - y
+			if (y < 0 && r < x) || (y > 0 && r > x) {
+				return new(big.Int).Sub(toBig(x), toBig(y))
+			}
+
+			return r
+		}
+	}
+
+	if isInteger(a) && isInteger(b) {
+		return normalizeInt(new(big.Int).Sub(toBig(a), toBig(b)))
+	}
+
+	return toFloat(a) - toFloat(b)
+}
+
+// multiplyNumbers multiplies two numbers.
+func multiplyNumbers(a any, b any) any {
+	if x, ok := a.(int64); ok {
+		if y, ok := b.(int64); ok {
+			if x == 0 || y == 0 {
+				return int64(0)
+			}
+
+			r := x * y
+			if r/y != x || (x == -1 && y == math.MinInt64) || (y == -1 && x == math.MinInt64) {
+				return new(big.Int).Mul(toBig(x), toBig(y))
+			}
+
+			return r
+		}
+	}
+
+	if isInteger(a) && isInteger(b) {
+		return normalizeInt(new(big.Int).Mul(toBig(a), toBig(b)))
+	}
+
+	return toFloat(a) * toFloat(b)
+}
+
+// divideNumbers divides a by b. Division always
+// produces a float, even for two integers.
+func divideNumbers(a any, b any) any {
+	if isInteger(a) && isInteger(b) && toBig(b).Sign() != 0 {
+		f, _ := new(big.Rat).SetFrac(toBig(a), toBig(b)).Float64()
+		return f
+	}
+
+	return toFloat(a) / toFloat(b)
+}
+
+// floorDivideNumbers divides a by b rounding towards
+// negative infinity. Dividing two integers produces an
+// integer, otherwise the result is a float.
+func floorDivideNumbers(a any, b any) (any, error) {
+	if isInteger(a) && isInteger(b) {
+		y := toBig(b)
+		if y.Sign() == 0 {
+			return nil, errors.New("Division by zero.")
+		}
+
+		q, m := new(big.Int).QuoRem(toBig(a), y, new(big.Int))
+		if m.Sign() != 0 && (m.Sign() < 0) != (y.Sign() < 0) {
+			q.Sub(q, big.NewInt(1))
+		}
+
+		return normalizeInt(q), nil
+	}
+
+	return math.Floor(toFloat(a) / toFloat(b)), nil
+}
+
+// negateNumber negates a number.
+func negateNumber(a any) any {
+	switch n := a.(type) {
+	case int64:
+		if n == math.MinInt64 {
+			return new(big.Int).Neg(toBig(n))
+		}
+
+		return -n
+	case *big.Int:
+		return normalizeInt(new(big.Int).Neg(n))
+	}
+
+	return -toFloat(a)
+}
+
+// compareNumbers compares two numbers, returning -1, 0
+// or 1 if a is less than, equal to or greater than b.
+// Integers and floats are compared by their exact values.
+// ok is false if the numbers are unordered, which is the
+// case when either of them is NaN.
+func compareNumbers(a any, b any) (result int, ok bool) {
+	if isInteger(a) && isInteger(b) {
+		return toBig(a).Cmp(toBig(b)), true
+	}
+
+	if math.IsNaN(toFloat(a)) || math.IsNaN(toFloat(b)) {
+		return 0, false
+	}
+
+	return toBigFloat(a).Cmp(toBigFloat(b)), true
+}
+
+// toBigFloat converts a number that is not NaN to
+// a *big.Float without losing precision.
+func toBigFloat(v any) *big.Float {
+	if f, ok := v.(float64); ok {
+		return new(big.Float).SetFloat64(f)
+	}
+
+	return new(big.Float).SetInt(toBig(v))
+}
+
+// moduloNumbers computes the remainder of dividing a by b.
+// The result has the same sign as b, so that
+// a == (a ~/ b) * b + a % b.
+func moduloNumbers(a any, b any) (any, error) {
+	if isInteger(a) && isInteger(b) {
+		y := toBig(b)
+		if y.Sign() == 0 {
+			return nil, errors.New("Division by zero.")
+		}
+
+		m := new(big.Int).Rem(toBig(a), y)
+		if m.Sign() != 0 && (m.Sign() < 0) != (y.Sign() < 0) {
+			m.Add(m, y)
+		}
+
+		return normalizeInt(m), nil
+	}
+
+	x, y := toFloat(a), toFloat(b)
+	m := math.Mod(x, y)
+	if m != 0 && (m < 0) != (y < 0) {
+		m += y
+	}
+
+	return m, nil
+}
+
+// powerNumbers raises a to the power of b. An integer
+// raised to a non-negative integer is an exact integer,
+// otherwise the result is a float.
+func powerNumbers(a any, b any) (any, error) {
+	if isInteger(a) && isInteger(b) && toBig(b).Sign() >= 0 {
+		x, y := toBig(a), toBig(b)
+
+		// the result has about x.BitLen() * y bits,
+		// except for 0, 1 and -1 which stay small.
+		if x.CmpAbs(big.NewInt(1)) > 0 {
+			bits := new(big.Int).Mul(big.NewInt(int64(x.BitLen()-1)), y)
+			if bits.Cmp(big.NewInt(maxPowerBits)) > 0 {
+				return nil, errors.New("result of ** too large")
+			}
+		}
+
+		return normalizeInt(new(big.Int).Exp(x, y, nil)), nil
+	}
+
+	return math.Pow(toFloat(a), toFloat(b)), nil
+}
+
+// maxPowerBits is the largest number of bits of an
+// integer computed by **, to keep a single power from
+// exhausting memory.
+const maxPowerBits = 1 << 20
+
+// toInteger converts an integral number to a *big.Int.
+// Floats are accepted as long as they have no fractional
+// part. ok is false if the value is not integral.
+func toInteger(v any) (n *big.Int, ok bool) {
+	if isInteger(v) {
+		return toBig(v), true
+	}
+
+	f, isFloat := v.(float64)
+	if !isFloat || math.IsInf(f, 0) || math.IsNaN(f) || f != math.Trunc(f) {
+		return nil, false
+	}
+
+	n, _ = new(big.Float).SetFloat64(f).Int(nil)
+	return n, true
+}
+
+// maxShift is the largest shift count accepted by the
+// shift operators, to keep a single shift from exhausting
+// memory.
+const maxShift = 1 << 20
+
+// bitwiseNumbers applies a bitwise operator to two integral
+// numbers. Negative integers behave as if they were stored
+// in two's complement with infinitely many sign bits.
+func bitwiseNumbers(operator token.TokenType, a any, b any) (any, error) {
+	x, ok := toInteger(a)
+	if !ok {
+		return nil, errors.New("operands must be integers")
+	}
+
+	y, ok := toInteger(b)
+	if !ok {
+		return nil, errors.New("operands must be integers")
+	}
+
+	r := new(big.Int)
+
+	switch operator {
+	case token.AMPERSAND:
+		r.And(x, y)
+	case token.PIPE:
+		r.Or(x, y)
+	case token.CARET:
+		r.Xor(x, y)
+	case token.LESS_LESS, token.GREATER_GREATER:
+		if y.Sign() < 0 {
+			return nil, errors.New("shift count must not be negative")
+		}
+
+		if y.Cmp(big.NewInt(maxShift)) > 0 {
+			return nil, errors.New("shift count too large")
+		}
+
+		if operator == token.LESS_LESS {
+			r.Lsh(x, uint(y.Int64()))
+		} else {
+			r.Rsh(x, uint(y.Int64()))
+		}
+	}
+
+	return normalizeInt(r), nil
+}
+
+// complementNumber computes the bitwise complement
+// of an integral number.
+func complementNumber(a any) (any, error) {
+	x, ok := toInteger(a)
+	if !ok {
+		return nil, errors.New("operand must be an integer")
+	}
+
+	return normalizeInt(new(big.Int).Not(x)), nil
+}
@@ -0,0 +1,156 @@
+package interpreter
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestPower(t *testing.T) {
+	tests := []struct {
+		source        string
+		expected      string
+		expectedError bool
+	}{
+		{"print 2 ** 10;", "1024\n", false},
+		{"print 2 ** 64;", "18446744073709551616\n", false},
+		{"print 2 ** -1;", "0.5\n", false},
+		{"print 1 ** 100000000000;", "1\n", false},
+		{"print (-1) ** 100000000001;", "-1\n", false},
+		{"print 0 ** 100000000000;", "0\n", false},
+		{"print 2 ** 1048576 > 0;", "true\n", false},
+		{"print 2 ** 1048577;", "", true},
+		{"print 2 ** 100000000000;", "", true},
+		{"print math.pow(10, 100000000000);", "", true},
+	}
+
+	for i, tt := range tests {
+		var output bytes.Buffer
+
+		interpreter := New()
+		interpreter.Stdout = &output
+
+		err := interpret(t, interpreter, tt.source)
+		if (err != nil) != tt.expectedError {
+			t.Fatalf("tests[%d] - error wrong. expected=%v, got=%v", i, tt.expectedError, err)
+		}
+
+		if output.String() != tt.expected {
+			t.Fatalf("tests[%d] - output wrong. expected=%q, got=%q", i, tt.expected, output.String())
+		}
+	}
+}
+
+func TestArithmetic(t *testing.T) {
+	tests := []struct {
+		source        string
+		expected      string
+		expectedError bool
+	}{
+		// mixing integers and floats yields a float.
+		{"print 1 + 2;", "3\n", false},
+		{"print 1 + 2.5;", "3.5\n", false},
+		{"print 3 * 0.5;", "1.5\n", false},
+		{"print 7 / 2;", "3.5\n", false},
+		{"print 6 / 3;", "2\n", false},
+		{"print 1 / 0;", "+Inf\n", false},
+
+		// integer division rounds towards negative infinity.
+		{"print 7 ~/ 2;", "3\n", false},
+		{"print -7 ~/ 2;", "-4\n", false},
+		{"print 7.5 ~/ 2;", "3\n", false},
+		{"print 7 ~/ 0;", "", true},
+		{"print (2 ** 70) ~/ 0;", "", true},
+
+		// integers are promoted instead of overflowing.
+		{"print 9223372036854775807 + 1;", "9223372036854775808\n", false},
+		{"print -9223372036854775807 - 2;", "-9223372036854775809\n", false},
+		{"print 9223372036854775807 * 2;", "18446744073709551614\n", false},
+		{"print 4294967296 * 4294967296;", "18446744073709551616\n", false},
+		{"print (9223372036854775807 + 1) - 1;", "9223372036854775807\n", false},
+		{"print (2 ** 70) ~/ (2 ** 69);", "2\n", false},
+
+		// equality compares numbers by value.
+		{"print 1 == 1.0;", "true\n", false},
+		{"print 2 ** 64 == 18446744073709551616.0;", "true\n", false},
+		{"print 2 ** 64 == 2 ** 64;", "true\n", false},
+		{"print 2 ** 64 == 2 ** 64 + 1;", "false\n", false},
+		{"print 9223372036854775807 + 1 != 9223372036854775807;", "true\n", false},
+		{`print 1 == "1";`, "false\n", false},
+		{"print 0.1 + 0.2 == 0.3;", "false\n", false},
+	}
+
+	for i, tt := range tests {
+		var output bytes.Buffer
+
+		interpreter := New()
+		interpreter.Stdout = &output
+
+		err := interpret(t, interpreter, tt.source)
+		if (err != nil) != tt.expectedError {
+			t.Fatalf("tests[%d] - error wrong for %v. expected=%v, got=%v", i, tt.source, tt.expectedError, err)
+		}
+
+		if output.String() != tt.expected {
+			t.Fatalf("tests[%d] - output wrong for %v. expected=%q, got=%q", i, tt.source, tt.expected, output.String())
+		}
+	}
+}
+
+func TestIntegerOperators(t *testing.T) {
+	tests := []struct {
+		source        string
+		expected      string
+		expectedError bool
+	}{
+		// the result of % has the sign of the divisor.
+		{"print 7 % 3;", "1\n", false},
+		{"print -7 % 3;", "2\n", false},
+		{"print 7 % -3;", "-2\n", false},
+		{"print -7 % -3;", "-1\n", false},
+		{"print -7.5 % 2;", "0.5\n", false},
+		{"print -(2 ** 70) % 3;", "2\n", false},
+		{"print 7 % 0;", "", true},
+
+		{"print 12 & 10;", "8\n", false},
+		{"print 12 | 10;", "14\n", false},
+		{"print 12 ^ 10;", "6\n", false},
+		{"print 1 << 3;", "8\n", false},
+		{"print -16 >> 2;", "-4\n", false},
+		{"print -1 & 255;", "255\n", false},
+		{"print 6.0 & 3;", "2\n", false},
+		{"print 1 << 70;", "1180591620717411303424\n", false},
+		{"print (1 << 70) >> 69;", "2\n", false},
+		{"print (2 ** 70) & (2 ** 70 + 1);", "1180591620717411303424\n", false},
+		{"print (2 ** 70) | 1;", "1180591620717411303425\n", false},
+		{"print (2 ** 70 + 3) ^ (2 ** 70);", "3\n", false},
+		{"print -(2 ** 70) >> 70;", "-1\n", false},
+
+		// operands must be integral.
+		{"print 1.5 & 1;", "", true},
+		{"print 1 | 0.5;", "", true},
+		{"print 1 << 2.5;", "", true},
+		{`print "a" ^ 1;`, "", true},
+		{"print 1 << -1;", "", true},
+		{"print 1 << 1048577;", "", true},
+
+		{"print 2 ** 3 ** 2;", "512\n", false},
+		{"print -2 ** 2;", "-4\n", false},
+		{"print (-2) ** 2;", "4\n", false},
+	}
+
+	for i, tt := range tests {
+		var output bytes.Buffer
+
+		interpreter := New()
+		interpreter.Stdout = &output
+
+		err := interpret(t, interpreter, tt.source)
+		if (err != nil) != tt.expectedError {
+			t.Fatalf("tests[%d] - error wrong for %v. expected=%v, got=%v", i, tt.source, tt.expectedError, err)
+		}
+
+		if output.String() != tt.expected {
+			t.Fatalf("tests[%d] - output wrong for %v. expected=%q, got=%q", i, tt.source, tt.expected, output.String())
+		}
+	}
+}
@@ -0,0 +1,255 @@
+package interpreter
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// processEnv contains the environment variables and the
+// working directory of a script. They start as those of
+// the process, but changing them does not change the
+// process, so that interpreters running at the same time
+// do not see each other's changes.
+type processEnv struct {
+	lock   sync.Mutex
+	loaded bool
+	vars   map[string]string
+	dir    string
+}
+
+// load copies the environment of the process
+// the first time it is used.
+func (e *processEnv) load() error {
+	if e.loaded {
+		return nil
+	}
+
+	dir, err := os.Getwd()
+	if err != nil {
+		return err
+	}
+
+	e.vars = make(map[string]string)
+	for _, entry := range os.Environ() {
+		name, value, _ := strings.Cut(entry, "=")
+		e.vars[name] = value
+	}
+
+	e.dir = dir
+	e.loaded = true
+	return nil
+}
+
+// environ returns the variables in the
+// form used by exec.Cmd.Env.
+func (e *processEnv) environ() []string {
+	var entries []string
+	for name, value := range e.vars {
+		entries = append(entries, name+"="+value)
+	}
+
+	sort.Strings(entries)
+	return entries
+}
+
+// use calls f with the loaded environment, holding its lock.
+func (e *processEnv) use(f func() (any, error)) (any, error) {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+
+	err := e.load()
+	if err != nil {
+		return nil, err
+	}
+
+	return f()
+}
+
+// checkProcessAccess checks if a native may run
+// commands or access the process environment.
+func (i *Interpreter) checkProcessAccess(function string) error {
+	if !i.Process {
+		return fmt.Errorf("%v: process access is disabled.", function)
+	}
+
+	return nil
+}
+
+// processNative creates a native requiring process
+// access and taking string arguments.
+func processNative(
+	name string,
+	params int,
+	f func(interpreter *Interpreter, arguments []string) (any, error),
+) *NativeFunction {
+	return &NativeFunction{
+		Name:   name,
+		Params: params,
+		Function: func(interpreter *Interpreter, arguments []any) (any, error) {
+			err := interpreter.checkProcessAccess(name)
+			if err != nil {
+				return nil, err
+			}
+
+			var strs []string
+			for n := range arguments {
+				s, err := stringArgument(name, arguments, n)
+				if err != nil {
+					return nil, err
+				}
+
+				strs = append(strs, s)
+			}
+
+			value, err := f(interpreter, strs)
+			if err != nil {
+				return nil, fmt.Errorf("%v: %w", name, err)
+			}
+
+			return value, nil
+		},
+	}
+}
+
+// runCommand runs a command in the working directory and
+// with the environment variables of the script, and waits
+// for it to finish, returning a map with its exit code
+// and its output. Exiting with a non-zero code is not an
+// error. Commands without a path are looked up in the
+// PATH of the process.
+func runCommand(ctx context.Context, env []string, dir string, name string, args []string) (any, error) {
+	var stdout, stderr bytes.Buffer
+
+	cmd := exec.CommandContext(ctx, name, args...)
+	cmd.Env = env
+	cmd.Dir = dir
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	err := cmd.Run()
+
+	var execError *exec.Error
+	if errors.As(err, &execError) {
+		return nil, fmt.Errorf("cannot run %v: %v", execError.Name, execError.Err)
+	}
+
+	var exitError *exec.ExitError
+	if err != nil && !errors.As(err, &exitError) {
+		return nil, err
+	}
+
+	result := NewMap()
+	result.Set("code", int64(cmd.ProcessState.ExitCode()))
+	result.Set("stdout", stdout.String())
+	result.Set("stderr", stderr.String())
+
+	return result, nil
+}
+
+// processNatives returns the natives running commands
+// and accessing the environment of the script.
+func processNatives() []*NativeFunction {
+	return []*NativeFunction{
+		// getenv returns nil for unset variables.
+		processNative("getenv", 1, func(interpreter *Interpreter, arguments []string) (any, error) {
+			env := interpreter.env
+			return env.use(func() (any, error) {
+				value, ok := env.vars[arguments[0]]
+				if !ok {
+					return nil, nil
+				}
+
+				return value, nil
+			})
+		}),
+		processNative("setenv", 2, func(interpreter *Interpreter, arguments []string) (any, error) {
+			if arguments[0] == "" || strings.ContainsAny(arguments[0], "=\x00") {
+				return nil, fmt.Errorf("invalid variable name %q.", arguments[0])
+			}
+
+			env := interpreter.env
+			return env.use(func() (any, error) {
+				env.vars[arguments[0]] = arguments[1]
+				return nil, nil
+			})
+		}),
+		processNative("cwd", 0, func(interpreter *Interpreter, arguments []string) (any, error) {
+			env := interpreter.env
+			return env.use(func() (any, error) {
+				return env.dir, nil
+			})
+		}),
+		// chdir changes the directory commands run in. Relative
+		// directories are relative to the working directory.
+		processNative("chdir", 1, func(interpreter *Interpreter, arguments []string) (any, error) {
+			env := interpreter.env
+			return env.use(func() (any, error) {
+				dir := arguments[0]
+				if !filepath.IsAbs(dir) {
+					dir = filepath.Join(env.dir, dir)
+				}
+
+				info, err := os.Stat(dir)
+				if err != nil {
+					return nil, err
+				}
+
+				if !info.IsDir() {
+					return nil, fmt.Errorf("%v is not a directory.", arguments[0])
+				}
+
+				env.dir = filepath.Clean(dir)
+				return nil, nil
+			})
+		}),
+		processNative("exec", Variadic, func(interpreter *Interpreter, arguments []string) (any, error) {
+			if len(arguments) == 0 {
+				return nil, errors.New("expected a command.")
+			}
+
+			var environ []string
+			var dir string
+			env := interpreter.env
+			_, err := env.use(func() (any, error) {
+				environ = env.environ()
+				dir = env.dir
+				return nil, nil
+			})
+			if err != nil {
+				return nil, err
+			}
+
+			var result any
+			interpreter.blocking(func() {
+				result, err = runCommand(interpreter.context(), environ, dir, arguments[0], arguments[1:])
+			})
+
+			if interpreter.context().Err() != nil {
+				return nil, interpreter.stopped()
+			}
+
+			if err != nil {
+				return nil, err
+			}
+
+			output := result.(*Map)
+			stdout, _ := output.Get("stdout")
+			stderr, _ := output.Get("stderr")
+
+			err = interpreter.charge(sizeOf(output) + sizeOf(stdout) + sizeOf(stderr))
+			if err != nil {
+				return nil, err
+			}
+
+			return result, nil
+		}),
+	}
+}
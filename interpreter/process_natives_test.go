@@ -0,0 +1,146 @@
+package interpreter
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestProcessAccess(t *testing.T) {
+	tests := []struct {
+		process       bool
+		native        string
+		arguments     []any
+		expectedError bool
+	}{
+		{false, "getenv", []any{"PATH"}, true},
+		{false, "cwd", nil, true},
+		{false, "exec", []any{"true"}, true},
+		{true, "getenv", []any{"PATH"}, false},
+		{true, "cwd", nil, false},
+		{true, "getenv", []any{int64(1)}, true},
+	}
+
+	for i, tt := range tests {
+		interpreter := New()
+		interpreter.Process = tt.process
+
+		native := interpreter.Globals.Values[tt.native].(*NativeFunction)
+
+		_, err := native.Call(interpreter, tt.arguments)
+		if (err != nil) != tt.expectedError {
+			t.Fatalf("tests[%d] - error wrong for %v. expected=%v, got=%v", i, tt.native, tt.expectedError, err)
+		}
+	}
+}
+
+func TestProcessNatives(t *testing.T) {
+	dir := t.TempDir()
+	err := os.Mkdir(filepath.Join(dir, "sub"), 0o755)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = os.WriteFile(filepath.Join(dir, "file"), nil, 0o644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		source        string
+		expected      string
+		expectedError bool
+	}{
+		{`var r = exec("sh", "-c", "echo out; echo err >&2; exit 3"); print get(r, "code"); print get(r, "stdout"); print get(r, "stderr");`, "3\nout\n\nerr\n\n", false},
+		{`print get(exec("true"), "code");`, "0\n", false},
+		{`print get(exec("echo", "a b", "c"), "stdout");`, "a b c\n\n", false},
+		{`exec("golox-missing-command");`, "", true},
+		{`exec();`, "", true},
+		{`exec("echo", 1);`, "", true},
+
+		{`print getenv("GOLOX_TEST_UNSET");`, "<nil>\n", false},
+		{`setenv("GOLOX_TEST", "a"); print getenv("GOLOX_TEST");`, "a\n", false},
+		{`setenv("GOLOX_TEST", "b"); print get(exec("sh", "-c", "echo $GOLOX_TEST"), "stdout");`, "b\n\n", false},
+		{`setenv("", "a");`, "", true},
+		{`setenv("A=B", "a");`, "", true},
+		{`setenv("A", 1);`, "", true},
+
+		{`print cwd() == "` + dir + `";`, "true\n", false},
+		{`chdir("sub"); print cwd() == "` + filepath.Join(dir, "sub") + `";`, "true\n", false},
+		{`chdir("sub"); chdir(".."); print cwd() == "` + dir + `";`, "true\n", false},
+		{`chdir("` + filepath.Join(dir, "sub") + `"); print get(exec("pwd"), "stdout") == "` + filepath.Join(dir, "sub") + `
+";`, "true\n", false},
+		{`chdir("missing");`, "", true},
+		{`chdir("file");`, "", true},
+	}
+
+	for i, tt := range tests {
+		var output bytes.Buffer
+
+		interpreter := New()
+		interpreter.Process = true
+		interpreter.Stdout = &output
+		interpreter.env.dir = dir
+		interpreter.env.loaded = true
+		interpreter.env.vars = map[string]string{"PATH": os.Getenv("PATH")}
+
+		err := interpret(t, interpreter, tt.source)
+		if (err != nil) != tt.expectedError {
+			t.Fatalf("tests[%d] - error wrong for %v. expected=%v, got=%v", i, tt.source, tt.expectedError, err)
+		}
+
+		if output.String() != tt.expected {
+			t.Fatalf("tests[%d] - output wrong for %v. expected=%q, got=%q", i, tt.source, tt.expected, output.String())
+		}
+	}
+}
+
+func TestProcessIsolation(t *testing.T) {
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dir := t.TempDir()
+	source := `setenv("GOLOX_ISOLATION", "set"); chdir("` + dir + `"); print getenv("GOLOX_ISOLATION"); print cwd() == "` + dir + `";`
+
+	var output bytes.Buffer
+	interpreter := New()
+	interpreter.Process = true
+	interpreter.Stdout = &output
+
+	err = interpret(t, interpreter, source)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if output.String() != "set\ntrue\n" {
+		t.Fatalf("output wrong. expected=%q, got=%q", "set\ntrue\n", output.String())
+	}
+
+	// neither the process nor other interpreters
+	// see the changes of the script.
+	if _, ok := os.LookupEnv("GOLOX_ISOLATION"); ok {
+		t.Fatalf("setenv changed the environment of the process")
+	}
+
+	current, err := os.Getwd()
+	if err != nil || current != wd {
+		t.Fatalf("chdir changed the working directory of the process to %v", current)
+	}
+
+	output.Reset()
+	other := New()
+	other.Process = true
+	other.Stdout = &output
+
+	err = interpret(t, other, `print getenv("GOLOX_ISOLATION"); print cwd() == "`+wd+`";`)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if output.String() != "<nil>\ntrue\n" {
+		t.Fatalf("output wrong. expected=%q, got=%q", "<nil>\ntrue\n", output.String())
+	}
+}
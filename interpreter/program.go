@@ -0,0 +1,112 @@
+package interpreter
+
+import (
+	"context"
+	"errors"
+	"golox/parser"
+	"golox/resolver"
+	"golox/scanner"
+	"golox/statement"
+	"io"
+)
+
+// ErrCompile is returned for sources that cannot be
+// compiled. The errors themselves are reported as
+// they are found.
+var ErrCompile = errors.New("Cannot compile script.")
+
+// Program is a scanned, parsed and resolved golox script.
+// Interpreters never modify a Program, so it can be run
+// by many interpreters at the same time.
+type Program struct {
+	Statements []statement.Stmt
+}
+
+// Compile compiles a golox source into a Program.
+func Compile(source string) (*Program, error) {
+	return CompileWith(source, nil)
+}
+
+// CompileWith compiles a golox source into a Program,
+// calling configure on the parser before parsing, for
+// instance to lower its limits. configure may be nil.
+func CompileWith(source string, configure func(parser *parser.Parser)) (*Program, error) {
+	scanner := scanner.New(source)
+	tokens := scanner.ScanTokens()
+	if scanner.HadError {
+		return nil, ErrCompile
+	}
+
+	parser := parser.Parser{
+		Tokens: tokens,
+	}
+	if configure != nil {
+		configure(&parser)
+	}
+
+	statements, isError := parser.Parse()
+	if isError {
+		return nil, ErrCompile
+	}
+
+	resolver := resolver.Resolver{}
+	if resolver.Resolve(statements) {
+		return nil, ErrCompile
+	}
+
+	return &Program{
+		Statements: statements,
+	}, nil
+}
+
+// Run runs a program in the global environment
+// of the interpreter.
+func (i *Interpreter) Run(program *Program) error {
+	return i.Interpret(program.Statements)
+}
+
+// Pool runs programs concurrently, each on a new
+// Interpreter, with a bounded number of programs
+// running at the same time.
+type Pool struct {
+	// Configure configures the interpreters before they
+	// run a program, for instance to grant capabilities
+	// or to set limits. It may be nil.
+	Configure func(interpreter *Interpreter)
+
+	slots chan struct{}
+}
+
+// NewPool creates a Pool running up to size
+// programs at the same time.
+func NewPool(size int, configure func(interpreter *Interpreter)) *Pool {
+	return &Pool{
+		Configure: configure,
+		slots:     make(chan struct{}, size),
+	}
+}
+
+// Run runs program on a new interpreter writing to stdout,
+// waiting for a running program to finish if the pool is
+// full. The program is stopped when ctx is done.
+func (p *Pool) Run(ctx context.Context, program *Program, stdout io.Writer) error {
+	select {
+	case p.slots <- struct{}{}:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+
+	defer func() {
+		<-p.slots
+	}()
+
+	interpreter := New()
+	if p.Configure != nil {
+		p.Configure(interpreter)
+	}
+
+	interpreter.Stdout = stdout
+	interpreter.Context = ctx
+
+	return interpreter.Run(program)
+}
@@ -0,0 +1,134 @@
+package interpreter
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"golox/parser"
+	"sync"
+	"testing"
+)
+
+// concurrentSource exercises closures, nested scopes,
+// big integers and natives, which all keep their state
+// in the interpreter running them.
+const concurrentSource = `
+fun fib(n) {
+	if (n < 2) return n;
+	return fib(n - 1) + fib(n - 2);
+}
+
+fun counter() {
+	var count = 0;
+	fun increment() {
+		count = count + 1;
+		return count;
+	}
+	return increment;
+}
+
+var next = counter();
+var total = 0;
+for (var i = 0; i < 100; i = i + 1) {
+	total = total + next();
+}
+
+var big = 9223372036854775807;
+var items = array();
+push(items, fib(15));
+push(items, total);
+push(items, big + big);
+print jsonStringify(items);
+print reFind("(\d+)", format("{:>5}", total));
+`
+
+const concurrentOutput = "[610,5050,18446744073709551614]\n[\"5050\", \"5050\"]\n"
+
+func TestConcurrentInterpreters(t *testing.T) {
+	program, err := Compile(concurrentSource)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var wg sync.WaitGroup
+	outputs := make([]bytes.Buffer, 20)
+
+	for n := range outputs {
+		wg.Add(1)
+		go func(n int) {
+			defer wg.Done()
+
+			interpreter := New()
+			interpreter.Stdout = &outputs[n]
+
+			err := interpreter.Run(program)
+			if err != nil {
+				t.Error(err)
+			}
+		}(n)
+	}
+
+	wg.Wait()
+
+	for n := range outputs {
+		if outputs[n].String() != concurrentOutput {
+			t.Fatalf("interpreter %d - output wrong. expected=%q, got=%q", n, concurrentOutput, outputs[n].String())
+		}
+	}
+}
+
+func TestPool(t *testing.T) {
+	program, err := Compile(concurrentSource)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	pool := NewPool(4, func(interpreter *Interpreter) {
+		interpreter.Limits.Steps = 1000000
+	})
+
+	var wg sync.WaitGroup
+	outputs := make([]bytes.Buffer, 20)
+
+	for n := range outputs {
+		wg.Add(1)
+		go func(n int) {
+			defer wg.Done()
+
+			err := pool.Run(context.Background(), program, &outputs[n])
+			if err != nil {
+				t.Error(err)
+			}
+		}(n)
+	}
+
+	wg.Wait()
+
+	for n := range outputs {
+		if outputs[n].String() != concurrentOutput {
+			t.Fatalf("run %d - output wrong. expected=%q, got=%q", n, concurrentOutput, outputs[n].String())
+		}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	err = pool.Run(ctx, program, &bytes.Buffer{})
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("canceled run - error wrong. expected=%v, got=%v", context.Canceled, err)
+	}
+}
+
+func TestCompileError(t *testing.T) {
+	_, err := Compile("var = 1;")
+	if !errors.Is(err, ErrCompile) {
+		t.Fatalf("error wrong. expected=%v, got=%v", ErrCompile, err)
+	}
+
+	_, err = CompileWith("fun f(a, b) {}", func(parser *parser.Parser) {
+		parser.MaxParameters = 1
+	})
+	if !errors.Is(err, ErrCompile) {
+		t.Fatalf("limited parameters - error wrong. expected=%v, got=%v", ErrCompile, err)
+	}
+}
@@ -0,0 +1,142 @@
+package interpreter
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"math/rand"
+	"time"
+	"unicode/utf8"
+)
+
+// alphanumerics is the default alphabet of randomString.
+const alphanumerics = "ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz0123456789"
+
+// newRandom creates a random source seeded from the time.
+func newRandom() *rand.Rand {
+	return rand.New(rand.NewSource(time.Now().UnixNano()))
+}
+
+// integerArgument returns the argument at index n as
+// a big integer, checking that it is an integer.
+func integerArgument(function string, arguments []any, n int) (*big.Int, error) {
+	if !isInteger(arguments[n]) {
+		return nil, fmt.Errorf("%v: argument %v must be an integer.", function, n+1)
+	}
+
+	return toBig(arguments[n]), nil
+}
+
+// randomNatives returns the natives generating random
+// values from the source of the interpreter.
+func randomNatives() []*NativeFunction {
+	return []*NativeFunction{
+		{
+			Name:   "random",
+			Params: 0,
+			Function: func(interpreter *Interpreter, arguments []any) (any, error) {
+				return interpreter.Random.Float64(), nil
+			},
+		},
+		{
+			// randomInt returns an integer between lo and hi,
+			// both included.
+			Name:   "randomInt",
+			Params: 2,
+			Function: func(interpreter *Interpreter, arguments []any) (any, error) {
+				lo, err := integerArgument("randomInt", arguments, 0)
+				if err != nil {
+					return nil, err
+				}
+
+				hi, err := integerArgument("randomInt", arguments, 1)
+				if err != nil {
+					return nil, err
+				}
+
+				if hi.Cmp(lo) < 0 {
+					return nil, errors.New("randomInt: argument 2 must not be less than argument 1.")
+				}
+
+				span := new(big.Int).Sub(hi, lo)
+				span.Add(span, big.NewInt(1))
+
+				n := new(big.Int).Rand(interpreter.Random, span)
+				return normalizeInt(n.Add(n, lo)), nil
+			},
+		},
+		{
+			Name:   "gaussian",
+			Params: 2,
+			Function: func(interpreter *Interpreter, arguments []any) (any, error) {
+				mean, err := floatArgument("gaussian", arguments, 0)
+				if err != nil {
+					return nil, err
+				}
+
+				stddev, err := floatArgument("gaussian", arguments, 1)
+				if err != nil {
+					return nil, err
+				}
+
+				return mean + stddev*interpreter.Random.NormFloat64(), nil
+			},
+		},
+		{
+			Name:   "randomString",
+			Params: Variadic,
+			Function: func(interpreter *Interpreter, arguments []any) (any, error) {
+				err := checkArgumentCount("randomString", arguments, 1, 2)
+				if err != nil {
+					return nil, err
+				}
+
+				length, err := intArgument("randomString", arguments, 0)
+				if err != nil {
+					return nil, err
+				}
+
+				if length < 0 {
+					return nil, errors.New("randomString: length must not be negative.")
+				}
+
+				if length > maxStringLength/utf8.UTFMax {
+					return nil, errors.New("randomString: length too large.")
+				}
+
+				alphabet := alphanumerics
+				if len(arguments) == 2 {
+					alphabet, err = stringArgument("randomString", arguments, 1)
+					if err != nil {
+						return nil, err
+					}
+				}
+
+				runes := []rune(alphabet)
+				if len(runes) == 0 && length > 0 {
+					return nil, errors.New("randomString: alphabet must not be empty.")
+				}
+
+				result := make([]rune, length)
+				for n := range result {
+					result[n] = runes[interpreter.Random.Intn(len(runes))]
+				}
+
+				return interpreter.allocate(string(result))
+			},
+		},
+		{
+			Name:   "seed",
+			Params: 1,
+			Function: func(interpreter *Interpreter, arguments []any) (any, error) {
+				seed, ok := arguments[0].(int64)
+				if !ok {
+					return nil, errors.New("seed: argument 1 must be an integer.")
+				}
+
+				interpreter.Random.Seed(seed)
+				return nil, nil
+			},
+		},
+	}
+}
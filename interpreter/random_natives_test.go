@@ -0,0 +1,69 @@
+package interpreter
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestSeed(t *testing.T) {
+	call := func(interpreter *Interpreter, name string, arguments ...any) any {
+		native := interpreter.Globals.Values[name].(*NativeFunction)
+
+		value, err := native.Call(interpreter, arguments)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		return value
+	}
+
+	a := New()
+	b := New()
+
+	call(a, "seed", int64(7))
+	call(b, "seed", int64(7))
+
+	for n := 0; n < 10; n++ {
+		x := call(a, "randomInt", int64(0), int64(1000))
+
+		// draws from another interpreter do not
+		// change the sequence of b.
+		call(New(), "random")
+
+		y := call(b, "randomInt", int64(0), int64(1000))
+		if x != y {
+			t.Fatalf("draw %d - values differ. a=%v, b=%v", n, x, y)
+		}
+	}
+}
+
+func TestRandomString(t *testing.T) {
+	tests := []struct {
+		source        string
+		expected      string
+		expectedError bool
+	}{
+		{"print len(randomString(8));", "8\n", false},
+		{`print randomString(3, "a");`, "aaa\n", false},
+		{`print randomString(0, "");`, "\n", false},
+		{"randomString(-1);", "", true},
+		{`randomString(1, "");`, "", true},
+		{"randomString(9223372036854775807);", "", true},
+	}
+
+	for i, tt := range tests {
+		var output bytes.Buffer
+
+		interpreter := New()
+		interpreter.Stdout = &output
+
+		err := interpret(t, interpreter, tt.source)
+		if (err != nil) != tt.expectedError {
+			t.Fatalf("tests[%d] - error wrong for %v. expected=%v, got=%v", i, tt.source, tt.expectedError, err)
+		}
+
+		if output.String() != tt.expected {
+			t.Fatalf("tests[%d] - output wrong for %v. expected=%q, got=%q", i, tt.source, tt.expected, output.String())
+		}
+	}
+}
@@ -0,0 +1,161 @@
+package interpreter
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"regexp/syntax"
+	"strings"
+)
+
+// maxPatterns is the number of compiled patterns
+// an interpreter caches.
+const maxPatterns = 256
+
+// compilePattern compiles a regular expression, reusing
+// the patterns compiled before by the interpreter.
+func (i *Interpreter) compilePattern(function string, pattern string) (*regexp.Regexp, error) {
+	if re, ok := i.patterns[pattern]; ok {
+		return re, nil
+	}
+
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		// the regexp package only tells the part of the
+		// pattern that is invalid, its position is where
+		// that part is found in the pattern.
+		var syntaxError *syntax.Error
+		if errors.As(err, &syntaxError) {
+			position := strings.Index(pattern, syntaxError.Expr)
+			if position < 0 {
+				return nil, fmt.Errorf(
+					"%v: invalid pattern: %v in `%v`.",
+					function, syntaxError.Code, syntaxError.Expr,
+				)
+			}
+
+			return nil, fmt.Errorf(
+				"%v: invalid pattern: %v in `%v` at position %v.",
+				function, syntaxError.Code, syntaxError.Expr, position,
+			)
+		}
+
+		return nil, fmt.Errorf("%v: invalid pattern: %v.", function, err)
+	}
+
+	if i.patterns == nil || len(i.patterns) >= maxPatterns {
+		i.patterns = make(map[string]*regexp.Regexp)
+	}
+
+	i.patterns[pattern] = re
+	return re, nil
+}
+
+// groups converts the indexes of a match and its
+// groups to an array of strings. Groups that did
+// not participate in the match are nil.
+func groups(s string, indexes []int) *Array {
+	array := &Array{}
+	for n := 0; n < len(indexes); n += 2 {
+		if indexes[n] < 0 {
+			array.Elements = append(array.Elements, nil)
+			continue
+		}
+
+		array.Elements = append(array.Elements, s[indexes[n]:indexes[n+1]])
+	}
+
+	return array
+}
+
+// patternNative creates a native taking a pattern,
+// a string and possibly other string arguments.
+func patternNative(
+	name string,
+	params int,
+	f func(re *regexp.Regexp, s string, arguments []string) any,
+) *NativeFunction {
+	return &NativeFunction{
+		Name:   name,
+		Params: params,
+		Function: func(interpreter *Interpreter, arguments []any) (any, error) {
+			var strs []string
+			for n := range arguments {
+				s, err := stringArgument(name, arguments, n)
+				if err != nil {
+					return nil, err
+				}
+
+				strs = append(strs, s)
+			}
+
+			re, err := interpreter.compilePattern(name, strs[0])
+			if err != nil {
+				return nil, err
+			}
+
+			value := f(re, strs[1], strs[2:])
+
+			// the matches are parts of the string, only
+			// the arrays and the replaced strings are new.
+			size := sizeOf(value)
+			if matches, ok := value.(*Array); ok {
+				for _, match := range matches.Elements {
+					if groups, ok := match.(*Array); ok {
+						size += sizeOf(groups)
+					}
+				}
+			}
+
+			err = interpreter.charge(size)
+			if err != nil {
+				return nil, err
+			}
+
+			return value, nil
+		},
+	}
+}
+
+// regexpNatives returns the natives matching
+// strings against regular expressions.
+func regexpNatives() []*NativeFunction {
+	return []*NativeFunction{
+		patternNative("reMatch", 2, func(re *regexp.Regexp, s string, arguments []string) any {
+			return re.MatchString(s)
+		}),
+		// reFind returns the first match followed by its
+		// groups, or nil if there is no match.
+		patternNative("reFind", 2, func(re *regexp.Regexp, s string, arguments []string) any {
+			indexes := re.FindStringSubmatchIndex(s)
+			if indexes == nil {
+				return nil
+			}
+
+			return groups(s, indexes)
+		}),
+		// reFindAll returns every match as
+		// reFind does, in an array.
+		patternNative("reFindAll", 2, func(re *regexp.Regexp, s string, arguments []string) any {
+			matches := &Array{}
+			for _, indexes := range re.FindAllStringSubmatchIndex(s, -1) {
+				matches.Elements = append(matches.Elements, groups(s, indexes))
+			}
+
+			return matches
+		}),
+		// reReplace replaces every match. $1 or ${name}
+		// in the replacement refer to the groups.
+		patternNative("reReplace", 3, func(re *regexp.Regexp, s string, arguments []string) any {
+			return re.ReplaceAllString(s, arguments[0])
+		}),
+		patternNative("reSplit", 2, func(re *regexp.Regexp, s string, arguments []string) any {
+			array := &Array{}
+			for _, part := range re.Split(s, -1) {
+				array.Elements = append(array.Elements, part)
+			}
+
+			return array
+		}),
+	}
+}
@@ -0,0 +1,88 @@
+package interpreter
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestCompilePattern(t *testing.T) {
+	tests := []struct {
+		pattern       string
+		expectedError string
+	}{
+		{`a+b`, ""},
+		{`(?P<key>\w+)=(\d+)`, ""},
+		{`ab(c`, "missing closing ) in `ab(c` at position 0."},
+		{`a[b`, "missing closing ] in `[b` at position 1."},
+		{`xa**`, "invalid nested repetition operator in `**` at position 2."},
+		{`abc)`, "unexpected ) in `abc)` at position 0."},
+		{`a{2,1}`, "invalid repeat count in `{2,1}` at position 1."},
+	}
+
+	interpreter := New()
+
+	for i, tt := range tests {
+		re, err := interpreter.compilePattern("test", tt.pattern)
+		if tt.expectedError == "" {
+			if err != nil {
+				t.Fatalf("tests[%d] - unexpected error for %v: %v", i, tt.pattern, err)
+			}
+
+			cached, _ := interpreter.compilePattern("test", tt.pattern)
+			if cached != re {
+				t.Fatalf("tests[%d] - pattern %v not cached", i, tt.pattern)
+			}
+
+			continue
+		}
+
+		if err == nil || !strings.Contains(err.Error(), tt.expectedError) {
+			t.Fatalf("tests[%d] - error wrong for %v. expected=%q, got=%v", i, tt.pattern, tt.expectedError, err)
+		}
+	}
+}
+
+func TestRegexpNatives(t *testing.T) {
+	tests := []struct {
+		source        string
+		expected      string
+		expectedError bool
+	}{
+		{`print reMatch("^a+$", "aaa");`, "true\n", false},
+		{`print reMatch("^a+$", "ab");`, "false\n", false},
+		{`print reFind("(\w+)=(\d+)?", "a=1 b=");`, "[\"a=1\", \"a\", \"1\"]\n", false},
+		{`print reFind("x", "abc");`, "<nil>\n", false},
+		{`print reFindAll("(\w+)=(\d+)?", "a=1 b=");`, "[[\"a=1\", \"a\", \"1\"], [\"b=\", \"b\", nil]]\n", false},
+		{`print reFindAll("x", "abc");`, "[]\n", false},
+		{`print reReplace("(\w+)=(\d+)", "a=1 b=2", "$2=$1");`, "1=a 2=b\n", false},
+		{`print reReplace("(?P<k>\w+)=", "a=1", "${k}:");`, "a:1\n", false},
+		{`print reReplace("x", "abc", "y");`, "abc\n", false},
+		{`print reSplit(",\s*", "a, b,c");`, "[\"a\", \"b\", \"c\"]\n", false},
+		{`print reSplit(",", "");`, "[\"\"]\n", false},
+
+		{`reFind("(", "a");`, "", true},
+		{`reFindAll("a**", "a");`, "", true},
+		{`reReplace("[a", "a", "b");`, "", true},
+		{`reSplit("a{2,1}", "a");`, "", true},
+		{`reFind(1, "a");`, "", true},
+		{`reReplace("a", "a", 1);`, "", true},
+		{`reSplit("a");`, "", true},
+	}
+
+	for i, tt := range tests {
+		var output bytes.Buffer
+
+		interpreter := New()
+		interpreter.Stdout = &output
+
+		err := interpret(t, interpreter, tt.source)
+		if (err != nil) != tt.expectedError {
+			t.Fatalf("tests[%d] - error wrong for %v. expected=%v, got=%v", i, tt.source, tt.expectedError, err)
+		}
+
+		if output.String() != tt.expected {
+			t.Fatalf("tests[%d] - output wrong for %v. expected=%q, got=%q", i, tt.source, tt.expected, output.String())
+		}
+	}
+}
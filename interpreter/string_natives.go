@@ -0,0 +1,335 @@
+package interpreter
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// Strings are indexed by rune, so that every index
+// refers to a whole character.
+
+// maxStringLength is the largest number of bytes of a
+// string built by a native from a length chosen by the
+// script, to keep a single call from exhausting memory.
+const maxStringLength = 1 << 26
+
+// stringTransform creates a native mapping a string
+// to another string.
+func stringTransform(name string, f func(string) string) *NativeFunction {
+	return &NativeFunction{
+		Name:   name,
+		Params: 1,
+		Function: func(interpreter *Interpreter, arguments []any) (any, error) {
+			s, err := stringArgument(name, arguments, 0)
+			if err != nil {
+				return nil, err
+			}
+
+			return interpreter.allocate(f(s))
+		},
+	}
+}
+
+// stringPredicate creates a native testing a string
+// against another string.
+func stringPredicate(name string, f func(string, string) bool) *NativeFunction {
+	return &NativeFunction{
+		Name:   name,
+		Params: 2,
+		Function: func(interpreter *Interpreter, arguments []any) (any, error) {
+			s, err := stringArgument(name, arguments, 0)
+			if err != nil {
+				return nil, err
+			}
+
+			t, err := stringArgument(name, arguments, 1)
+			if err != nil {
+				return nil, err
+			}
+
+			return f(s, t), nil
+		},
+	}
+}
+
+// numberPrefixes maps the prefixes of integers
+// that are not decimal to their base.
+var numberPrefixes = map[string]int{
+	"0x": 16,
+	"0b": 2,
+	"0o": 8,
+}
+
+// parseNumber parses a decimal number or an integer with
+// a 0x, 0b or 0o prefix, returning nil if s is not a number.
+func parseNumber(s string) any {
+	s = strings.TrimSpace(s)
+
+	sign, digits := "", s
+	if strings.HasPrefix(s, "-") || strings.HasPrefix(s, "+") {
+		sign, digits = s[:1], s[1:]
+	}
+
+	base := 10
+	if len(digits) > 2 {
+		if b, ok := numberPrefixes[strings.ToLower(digits[:2])]; ok {
+			base, digits = b, digits[2:]
+		}
+	}
+
+	if n, ok := new(big.Int).SetString(sign+digits, base); ok {
+		return normalizeInt(n)
+	}
+
+	if base != 10 || strings.Trim(digits, "0123456789.eE+-") != "" {
+		return nil
+	}
+
+	f, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return nil
+	}
+
+	return f
+}
+
+// stringNatives returns the natives operating on strings.
+func stringNatives() []*NativeFunction {
+	return []*NativeFunction{
+		stringTransform("trim", strings.TrimSpace),
+		stringTransform("upper", strings.ToUpper),
+		stringTransform("lower", strings.ToLower),
+
+		stringPredicate("startsWith", strings.HasPrefix),
+		stringPredicate("endsWith", strings.HasSuffix),
+
+		{
+			Name:   "substr",
+			Params: Variadic,
+			Function: func(interpreter *Interpreter, arguments []any) (any, error) {
+				err := checkArgumentCount("substr", arguments, 2, 3)
+				if err != nil {
+					return nil, err
+				}
+
+				s, err := stringArgument("substr", arguments, 0)
+				if err != nil {
+					return nil, err
+				}
+
+				start, err := intArgument("substr", arguments, 1)
+				if err != nil {
+					return nil, err
+				}
+
+				runes := []rune(s)
+				if start < 0 || start > len(runes) {
+					return nil, fmt.Errorf("substr: start index %v out of range.", start)
+				}
+
+				end := len(runes)
+				if len(arguments) == 3 {
+					length, err := intArgument("substr", arguments, 2)
+					if err != nil {
+						return nil, err
+					}
+
+					if length < 0 {
+						return nil, errors.New("substr: length must not be negative.")
+					}
+
+					// length is compared to the runes left rather
+					// than added to start, which could overflow.
+					if length < end-start {
+						end = start + length
+					}
+				}
+
+				return string(runes[start:end]), nil
+			},
+		},
+		{
+			Name:   "indexOf",
+			Params: 2,
+			Function: func(interpreter *Interpreter, arguments []any) (any, error) {
+				s, err := stringArgument("indexOf", arguments, 0)
+				if err != nil {
+					return nil, err
+				}
+
+				sub, err := stringArgument("indexOf", arguments, 1)
+				if err != nil {
+					return nil, err
+				}
+
+				index := strings.Index(s, sub)
+				if index < 0 {
+					return int64(-1), nil
+				}
+
+				return int64(utf8.RuneCountInString(s[:index])), nil
+			},
+		},
+		{
+			Name:   "split",
+			Params: 2,
+			Function: func(interpreter *Interpreter, arguments []any) (any, error) {
+				s, err := stringArgument("split", arguments, 0)
+				if err != nil {
+					return nil, err
+				}
+
+				sep, err := stringArgument("split", arguments, 1)
+				if err != nil {
+					return nil, err
+				}
+
+				array := &Array{}
+				for _, part := range strings.Split(s, sep) {
+					array.Elements = append(array.Elements, part)
+				}
+
+				return interpreter.allocate(array)
+			},
+		},
+		{
+			Name:   "join",
+			Params: 2,
+			Function: func(interpreter *Interpreter, arguments []any) (any, error) {
+				array, err := arrayArgument("join", arguments, 0)
+				if err != nil {
+					return nil, err
+				}
+
+				sep, err := stringArgument("join", arguments, 1)
+				if err != nil {
+					return nil, err
+				}
+
+				var parts []string
+				for n, element := range array.Elements {
+					part, ok := element.(string)
+					if !ok {
+						return nil, fmt.Errorf("join: element %v must be a string.", n)
+					}
+
+					parts = append(parts, part)
+				}
+
+				return interpreter.allocate(strings.Join(parts, sep))
+			},
+		},
+		{
+			Name:   "replace",
+			Params: 3,
+			Function: func(interpreter *Interpreter, arguments []any) (any, error) {
+				var s [3]string
+				for n := range s {
+					var err error
+					s[n], err = stringArgument("replace", arguments, n)
+					if err != nil {
+						return nil, err
+					}
+				}
+
+				return interpreter.allocate(strings.ReplaceAll(s[0], s[1], s[2]))
+			},
+		},
+		{
+			Name:   "repeat",
+			Params: 2,
+			Function: func(interpreter *Interpreter, arguments []any) (any, error) {
+				s, err := stringArgument("repeat", arguments, 0)
+				if err != nil {
+					return nil, err
+				}
+
+				count, err := intArgument("repeat", arguments, 1)
+				if err != nil {
+					return nil, err
+				}
+
+				if count < 0 {
+					return nil, errors.New("repeat: count must not be negative.")
+				}
+
+				if count > 0 && len(s) > maxStringLength/count {
+					return nil, errors.New("repeat: result too long.")
+				}
+
+				return interpreter.allocate(strings.Repeat(s, count))
+			},
+		},
+		{
+			Name:   "charAt",
+			Params: 2,
+			Function: func(interpreter *Interpreter, arguments []any) (any, error) {
+				s, err := stringArgument("charAt", arguments, 0)
+				if err != nil {
+					return nil, err
+				}
+
+				index, err := intArgument("charAt", arguments, 1)
+				if err != nil {
+					return nil, err
+				}
+
+				runes := []rune(s)
+				if index < 0 || index >= len(runes) {
+					return nil, fmt.Errorf("charAt: index %v out of range.", index)
+				}
+
+				return string(runes[index]), nil
+			},
+		},
+		{
+			Name:   "ord",
+			Params: 1,
+			Function: func(interpreter *Interpreter, arguments []any) (any, error) {
+				s, err := stringArgument("ord", arguments, 0)
+				if err != nil {
+					return nil, err
+				}
+
+				if utf8.RuneCountInString(s) != 1 {
+					return nil, errors.New("ord: expected a single character.")
+				}
+
+				r, _ := utf8.DecodeRuneInString(s)
+				return int64(r), nil
+			},
+		},
+		{
+			Name:   "chr",
+			Params: 1,
+			Function: func(interpreter *Interpreter, arguments []any) (any, error) {
+				code, err := intArgument("chr", arguments, 0)
+				if err != nil {
+					return nil, err
+				}
+
+				if code < 0 || code > utf8.MaxRune || !utf8.ValidRune(rune(code)) {
+					return nil, fmt.Errorf("chr: invalid character code %v.", code)
+				}
+
+				return string(rune(code)), nil
+			},
+		},
+		{
+			Name:   "parseNumber",
+			Params: 1,
+			Function: func(interpreter *Interpreter, arguments []any) (any, error) {
+				s, err := stringArgument("parseNumber", arguments, 0)
+				if err != nil {
+					return nil, err
+				}
+
+				return parseNumber(s), nil
+			},
+		},
+	}
+}
@@ -0,0 +1,67 @@
+package interpreter
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestStringNatives(t *testing.T) {
+	tests := []struct {
+		source        string
+		expected      string
+		expectedError bool
+	}{
+		{`print trim("  ab ");`, "ab\n", false},
+		{`print upper("ab") + lower("CD");`, "ABcd\n", false},
+		{`print startsWith("golox", "go");`, "true\n", false},
+		{`print endsWith("golox", "go");`, "false\n", false},
+		{`print substr("héllo", 1, 3);`, "éll\n", false},
+		{`print substr("héllo", 2);`, "llo\n", false},
+		{`print substr("abc", 3);`, "\n", false},
+		{`print substr("abc", 1, 10);`, "bc\n", false},
+		{`print substr("abc", 1, 9223372036854775807);`, "bc\n", false},
+		{`print indexOf("héllo", "l");`, "2\n", false},
+		{`print indexOf("hello", "z");`, "-1\n", false},
+		{`print join(split("a,b,c", ","), "-");`, "a-b-c\n", false},
+		{`print replace("aXbX", "X", "-");`, "a-b-\n", false},
+		{`print repeat("ab", 3);`, "ababab\n", false},
+		{`print repeat("ab", 0);`, "\n", false},
+		{`print charAt("héllo", 1);`, "é\n", false},
+		{`print ord("é");`, "233\n", false},
+		{`print chr(233);`, "é\n", false},
+		{`print parseNumber("0x1f");`, "31\n", false},
+		{`print parseNumber("-2.5");`, "-2.5\n", false},
+		{`print parseNumber("abc") == nil;`, "true\n", false},
+
+		// out-of-range indices.
+		{`substr("abc", 4);`, "", true},
+		{`substr("abc", -1);`, "", true},
+		{`substr("abc", 0, -1);`, "", true},
+		{`charAt("abc", 3);`, "", true},
+		{`charAt("abc", -1);`, "", true},
+		{`chr(-1);`, "", true},
+		{`chr(1114112);`, "", true},
+
+		{`repeat("ab", -1);`, "", true},
+		{`repeat("ab", 4611686018427387904);`, "", true},
+		{`join(array("a", 1), ",");`, "", true},
+		{`ord("ab");`, "", true},
+		{`upper(1);`, "", true},
+	}
+
+	for i, tt := range tests {
+		var output bytes.Buffer
+
+		interpreter := New()
+		interpreter.Stdout = &output
+
+		err := interpret(t, interpreter, tt.source)
+		if (err != nil) != tt.expectedError {
+			t.Fatalf("tests[%d] - error wrong for %v. expected=%v, got=%v", i, tt.source, tt.expectedError, err)
+		}
+
+		if output.String() != tt.expected {
+			t.Fatalf("tests[%d] - output wrong for %v. expected=%q, got=%q", i, tt.source, tt.expected, output.String())
+		}
+	}
+}
@@ -0,0 +1,181 @@
+package interpreter
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// ExitError is returned when a script calls exit.
+type ExitError struct {
+	Code int
+}
+
+func (e *ExitError) Error() string {
+	return fmt.Sprintf("exit status %v", e.Code)
+}
+
+// stdout returns the output of the script.
+func (i *Interpreter) stdout() io.Writer {
+	if i.Stdout == nil {
+		return os.Stdout
+	}
+
+	return i.Stdout
+}
+
+// stdinReader reads lines from the standard input for the
+// tasks of a script. Reads happen on their own goroutine so
+// that the tasks waiting for a line release the lock of the
+// scheduler and can be stopped.
+type stdinReader struct {
+	// turn is held by the task reading a line, so
+	// that a single goroutine reads at a time.
+	turn chan struct{}
+
+	// pending receives the line being read, which is
+	// kept for the next task if the task waiting for it
+	// is stopped. It is only accessed by the task
+	// holding turn.
+	pending chan stdinLine
+}
+
+// stdinLine is a line read from the standard input.
+type stdinLine struct {
+	value any
+	err   error
+}
+
+func newStdinReader() *stdinReader {
+	return &stdinReader{
+		turn: make(chan struct{}, 1),
+	}
+}
+
+// readLine reads a line from reader without its line
+// ending, returning nil at the end of the input.
+func readLine(reader *bufio.Reader) (any, error) {
+	line, err := reader.ReadString('\n')
+	if err == io.EOF && line == "" {
+		return nil, nil
+	}
+
+	if err != nil && err != io.EOF {
+		return nil, err
+	}
+
+	line = strings.TrimSuffix(line, "\n")
+	return strings.TrimSuffix(line, "\r"), nil
+}
+
+// readStdin reads a line from the standard input without
+// its line ending, returning nil at the end of the input.
+func (i *Interpreter) readStdin(function string) (any, error) {
+	if i.Stdin == nil {
+		return nil, nil
+	}
+
+	r := i.stdin
+	stdin := i.Stdin
+
+	var line stdinLine
+	var err error
+	i.blocking(func() {
+		select {
+		case r.turn <- struct{}{}:
+		case <-i.context().Done():
+			err = i.stopped()
+			return
+		}
+
+		defer func() {
+			<-r.turn
+		}()
+
+		if r.pending == nil {
+			pending := make(chan stdinLine, 1)
+			go func() {
+				value, err := readLine(stdin)
+				pending <- stdinLine{value, err}
+			}()
+
+			r.pending = pending
+		}
+
+		select {
+		case line = <-r.pending:
+			r.pending = nil
+		case <-i.context().Done():
+			err = i.stopped()
+		}
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	if line.err != nil {
+		return nil, fmt.Errorf("%v: %v", function, line.err)
+	}
+
+	return i.allocate(line.value)
+}
+
+// systemNatives returns the natives for the
+// arguments and the standard input of a script.
+func systemNatives() []*NativeFunction {
+	return []*NativeFunction{
+		{
+			Name:   "argc",
+			Params: 0,
+			Function: func(interpreter *Interpreter, arguments []any) (any, error) {
+				return int64(len(interpreter.Args)), nil
+			},
+		},
+		{
+			Name:   "argv",
+			Params: 1,
+			Function: func(interpreter *Interpreter, arguments []any) (any, error) {
+				index, err := intArgument("argv", arguments, 0)
+				if err != nil {
+					return nil, err
+				}
+
+				if index < 0 || index >= len(interpreter.Args) {
+					return nil, fmt.Errorf("argv: index %v out of range.", index)
+				}
+
+				return interpreter.Args[index], nil
+			},
+		},
+		{
+			Name:   "input",
+			Params: 1,
+			Function: func(interpreter *Interpreter, arguments []any) (any, error) {
+				prompt, err := stringArgument("input", arguments, 0)
+				if err != nil {
+					return nil, err
+				}
+
+				fmt.Fprint(interpreter.stdout(), prompt)
+				return interpreter.readStdin("input")
+			},
+		},
+		{
+			Name:   "exit",
+			Params: 1,
+			Function: func(interpreter *Interpreter, arguments []any) (any, error) {
+				code, err := intArgument("exit", arguments, 0)
+				if err != nil {
+					return nil, err
+				}
+
+				return nil, &ExitError{
+					Code: code,
+				}
+			},
+		},
+	}
+}
@@ -0,0 +1,162 @@
+package interpreter
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSystemNatives(t *testing.T) {
+	tests := []struct {
+		source        string
+		args          []string
+		stdin         string
+		expected      string
+		expectedError bool
+	}{
+		{"print argc();", nil, "", "0\n", false},
+		{"print argc();", []string{"a", "b"}, "", "2\n", false},
+		{"print argv(1);", []string{"a", "b"}, "", "b\n", false},
+		{"argv(2);", []string{"a", "b"}, "", "", true},
+		{"argv(-1);", []string{"a", "b"}, "", "", true},
+		{`print input("name? ");`, nil, "golox\n", "name? golox\n", false},
+		{`print input("") + input("");`, nil, "a\r\nb", "ab\n", false},
+		{`print input("") == nil;`, nil, "", "true\n", false},
+		{"print readLine(); print readLine();", nil, "a\nb\n", "a\nb\n", false},
+		{"print readLine() == nil;", nil, "", "true\n", false},
+		{"input(1);", nil, "", "", true},
+		{"exit(\"a\");", nil, "", "", true},
+	}
+
+	for i, tt := range tests {
+		var output bytes.Buffer
+
+		interpreter := New()
+		interpreter.Args = tt.args
+		interpreter.Stdin = bufio.NewReader(strings.NewReader(tt.stdin))
+		interpreter.Stdout = &output
+
+		err := interpret(t, interpreter, tt.source)
+		if (err != nil) != tt.expectedError {
+			t.Fatalf("tests[%d] - error wrong for %v. expected=%v, got=%v", i, tt.source, tt.expectedError, err)
+		}
+
+		if output.String() != tt.expected {
+			t.Fatalf("tests[%d] - output wrong for %v. expected=%q, got=%q", i, tt.source, tt.expected, output.String())
+		}
+	}
+}
+
+func TestExit(t *testing.T) {
+	var output bytes.Buffer
+
+	interpreter := New()
+	interpreter.Stdout = &output
+
+	err := interpret(t, interpreter, `print "before"; exit(3); print "after";`)
+
+	var exit *ExitError
+	if !errors.As(err, &exit) || exit.Code != 3 {
+		t.Fatalf("error wrong. expected exit status 3, got=%v", err)
+	}
+
+	if output.String() != "before\n" {
+		t.Fatalf("output wrong. expected=%q, got=%q", "before\n", output.String())
+	}
+}
+
+func TestStdinBlocking(t *testing.T) {
+	stdin, input := io.Pipe()
+	defer input.Close()
+
+	var output bytes.Buffer
+
+	interpreter := New()
+	interpreter.Stdin = bufio.NewReader(stdin)
+	interpreter.Stdout = &output
+
+	// the task waiting for a line lets the
+	// other tasks run in the meantime.
+	done := make(chan error)
+	go func() {
+		done <- interpret(t, interpreter, `
+		fun read() { return readLine(); }
+		fun count() {
+			var sum = 0;
+			for (var i = 1; i <= 1000; i++) sum += i;
+			return sum;
+		}
+		var reader = spawn read();
+		print wait(spawn count());
+		print wait(reader);
+		`)
+	}()
+
+	deadline := time.Now().Add(time.Second)
+	for {
+		interpreter.scheduler.lock.Lock()
+		counted := output.String() == "500500\n"
+		interpreter.scheduler.lock.Unlock()
+
+		if counted {
+			break
+		}
+
+		if time.Now().After(deadline) {
+			t.Fatalf("counting task blocked by the reading task")
+		}
+
+		time.Sleep(time.Millisecond)
+	}
+
+	io.WriteString(input, "line\n")
+
+	err := <-done
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if output.String() != "500500\nline\n" {
+		t.Fatalf("output wrong. got=%q", output.String())
+	}
+}
+
+func TestStdinStopped(t *testing.T) {
+	stdin, input := io.Pipe()
+	defer input.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	interpreter := New()
+	interpreter.Stdin = bufio.NewReader(stdin)
+	interpreter.Stdout = &bytes.Buffer{}
+	interpreter.Context = ctx
+
+	err := interpret(t, interpreter, `input("? ");`)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("error wrong. expected=%v, got=%v", context.DeadlineExceeded, err)
+	}
+
+	// the line read for the stopped script
+	// is kept for the next read.
+	interpreter.Context = nil
+	go io.WriteString(input, "kept\nnext\n")
+
+	var output bytes.Buffer
+	interpreter.Stdout = &output
+
+	err = interpret(t, interpreter, "print readLine(); print readLine();")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if output.String() != "kept\nnext\n" {
+		t.Fatalf("output wrong. got=%q", output.String())
+	}
+}
@@ -0,0 +1,331 @@
+package interpreter
+
+import (
+	"errors"
+	"fmt"
+	"golox/ast"
+	"reflect"
+	"runtime"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Tasks run golox functions on their own goroutines. Each
+// task has its own Interpreter, and so its own scopes, but
+// tasks share their global environment and the values they
+// pass to each other. Only one task runs golox code at a
+// time, holding the lock of the scheduler: natives that
+// block, like recv or sleep, release it so that the other
+// tasks can run meanwhile, and long running tasks
+// regularly let the others run.
+//
+// Tasks still running when the script ends are stopped,
+// as if the context of the interpreter was done.
+
+// yieldInterval is the number of steps after
+// which a task lets the other tasks run.
+const yieldInterval = 1024
+
+// scheduler is shared by an interpreter, the tasks
+// it spawns and the modules it imports.
+type scheduler struct {
+	lock sync.Mutex
+
+	// tasks is the number of running tasks.
+	tasks atomic.Int64
+}
+
+// yield lets the other tasks run, if there are any.
+func (s *scheduler) yield() {
+	if s.tasks.Load() == 0 {
+		return
+	}
+
+	s.lock.Unlock()
+	runtime.Gosched()
+	s.lock.Lock()
+}
+
+// blocking runs f, which may block, letting
+// the other tasks run in the meantime.
+func (i *Interpreter) blocking(f func()) {
+	i.scheduler.lock.Unlock()
+	defer i.scheduler.lock.Lock()
+
+	f()
+}
+
+// Task is a function call running on its own goroutine.
+type Task struct {
+	done   chan struct{}
+	result any
+	err    error
+}
+
+func (t *Task) String() string {
+	return "<task>"
+}
+
+// Channel passes values between tasks.
+type Channel struct {
+	ch chan any
+
+	// closed is only accessed by the task holding the
+	// lock, unlike ch which tasks use while blocked.
+	closed bool
+}
+
+func (c *Channel) String() string {
+	return fmt.Sprintf("<chan %v>", cap(c.ch))
+}
+
+// newTask creates the interpreter of a task spawned by i.
+func (i *Interpreter) newTask() *Interpreter {
+	task := *i
+	task.Environment = i.Globals
+	task.depth = 0
+
+	return &task
+}
+
+// VisitSpawnExpr starts a task calling a function. The
+// callee and the arguments are evaluated by the caller.
+func (i *Interpreter) VisitSpawnExpr(expr *ast.Spawn) (any, error) {
+	function, arguments, err := i.prepareCall(expr.Call)
+	if err != nil {
+		return nil, err
+	}
+
+	task := &Task{
+		done: make(chan struct{}),
+	}
+
+	interpreter := i.newTask()
+	i.scheduler.tasks.Add(1)
+
+	go func() {
+		interpreter.scheduler.lock.Lock()
+		defer interpreter.scheduler.lock.Unlock()
+
+		task.result, task.err = interpreter.call(function, arguments)
+
+		interpreter.scheduler.tasks.Add(-1)
+		close(task.done)
+	}()
+
+	return task, nil
+}
+
+// channelArgument returns the argument at index n,
+// checking that it is a channel.
+func channelArgument(function string, arguments []any, n int) (*Channel, error) {
+	c, ok := arguments[n].(*Channel)
+	if !ok {
+		return nil, fmt.Errorf("%v: argument %v must be a channel.", function, n+1)
+	}
+
+	return c, nil
+}
+
+// send sends a value on a channel, failing if
+// the channel is closed before the value is sent.
+func (i *Interpreter) send(c *Channel, value any) (err error) {
+	if c.closed {
+		return errors.New("send: channel is closed.")
+	}
+
+	defer func() {
+		if recover() != nil {
+			err = errors.New("send: channel is closed.")
+		}
+	}()
+
+	i.blocking(func() {
+		select {
+		case c.ch <- value:
+		case <-i.context().Done():
+			err = i.stopped()
+		}
+	})
+
+	return err
+}
+
+// taskNatives returns the natives
+// communicating between tasks.
+func taskNatives() []*NativeFunction {
+	return []*NativeFunction{
+		{
+			Name:   "chan",
+			Params: Variadic,
+			Function: func(interpreter *Interpreter, arguments []any) (any, error) {
+				err := checkArgumentCount("chan", arguments, 0, 1)
+				if err != nil {
+					return nil, err
+				}
+
+				capacity := 0
+				if len(arguments) == 1 {
+					capacity, err = intArgument("chan", arguments, 0)
+					if err != nil {
+						return nil, err
+					}
+				}
+
+				if capacity < 0 {
+					return nil, errors.New("chan: capacity must not be negative.")
+				}
+
+				return &Channel{
+					ch: make(chan any, capacity),
+				}, nil
+			},
+		},
+		{
+			Name:   "send",
+			Params: 2,
+			Function: func(interpreter *Interpreter, arguments []any) (any, error) {
+				c, err := channelArgument("send", arguments, 0)
+				if err != nil {
+					return nil, err
+				}
+
+				return nil, interpreter.send(c, arguments[1])
+			},
+		},
+		{
+			// recv returns nil once the channel
+			// is closed and empty.
+			Name:   "recv",
+			Params: 1,
+			Function: func(interpreter *Interpreter, arguments []any) (any, error) {
+				c, err := channelArgument("recv", arguments, 0)
+				if err != nil {
+					return nil, err
+				}
+
+				var value any
+				interpreter.blocking(func() {
+					select {
+					case value = <-c.ch:
+					case <-interpreter.context().Done():
+						err = interpreter.stopped()
+					}
+				})
+
+				return value, err
+			},
+		},
+		{
+			// select receives from the first ready channel of
+			// an array, returning the index of the channel and
+			// the value received, or nil after the optional
+			// timeout in seconds.
+			Name:   "select",
+			Params: Variadic,
+			Function: func(interpreter *Interpreter, arguments []any) (any, error) {
+				err := checkArgumentCount("select", arguments, 1, 2)
+				if err != nil {
+					return nil, err
+				}
+
+				channels, err := arrayArgument("select", arguments, 0)
+				if err != nil {
+					return nil, err
+				}
+
+				var cases []reflect.SelectCase
+				for n, element := range channels.Elements {
+					c, ok := element.(*Channel)
+					if !ok {
+						return nil, fmt.Errorf("select: element %v must be a channel.", n)
+					}
+
+					cases = append(cases, reflect.SelectCase{
+						Dir:  reflect.SelectRecv,
+						Chan: reflect.ValueOf(c.ch),
+					})
+				}
+
+				cases = append(cases, reflect.SelectCase{
+					Dir:  reflect.SelectRecv,
+					Chan: reflect.ValueOf(interpreter.context().Done()),
+				})
+
+				if len(arguments) == 2 {
+					seconds, err := floatArgument("select", arguments, 1)
+					if err != nil {
+						return nil, err
+					}
+
+					timer := time.NewTimer(toDuration(seconds))
+					defer timer.Stop()
+
+					cases = append(cases, reflect.SelectCase{
+						Dir:  reflect.SelectRecv,
+						Chan: reflect.ValueOf(timer.C),
+					})
+				}
+
+				var chosen int
+				var value reflect.Value
+				interpreter.blocking(func() {
+					chosen, value, _ = reflect.Select(cases)
+				})
+
+				switch {
+				case chosen == len(channels.Elements):
+					return nil, interpreter.stopped()
+				case chosen > len(channels.Elements):
+					return nil, nil
+				}
+
+				var received any
+				if value.IsValid() && !value.IsNil() {
+					received = value.Interface()
+				}
+
+				return &Array{
+					Elements: []any{int64(chosen), received},
+				}, nil
+			},
+		},
+		{
+			// wait waits for a task to finish, returning the
+			// value returned by its function or its error.
+			Name:   "wait",
+			Params: 1,
+			Function: func(interpreter *Interpreter, arguments []any) (any, error) {
+				task, ok := arguments[0].(*Task)
+				if !ok {
+					return nil, errors.New("wait: argument 1 must be a task.")
+				}
+
+				var err error
+				interpreter.blocking(func() {
+					select {
+					case <-task.done:
+					case <-interpreter.context().Done():
+						err = interpreter.stopped()
+					}
+				})
+
+				if err != nil {
+					return nil, err
+				}
+
+				return task.result, task.err
+			},
+		},
+	}
+}
+
+// closeChannel closes a channel. Closing
+// a closed channel does nothing.
+func closeChannel(c *Channel) {
+	if !c.closed {
+		c.closed = true
+		close(c.ch)
+	}
+}
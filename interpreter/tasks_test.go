@@ -0,0 +1,138 @@
+package interpreter
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestTasks(t *testing.T) {
+	tests := []struct {
+		source   string
+		expected string
+	}{
+		{`
+		fun square(n) { return n * n; }
+		var a = spawn square(3);
+		var b = spawn square(4);
+		print wait(a) + wait(b);
+		`, "25\n"},
+		{`
+		var c = chan();
+		fun produce(n) {
+			for (var i = 1; i <= n; i++) send(c, i);
+			close(c);
+		}
+		spawn produce(100);
+		var sum = 0;
+		var value = recv(c);
+		while (value != nil) {
+			sum += value;
+			value = recv(c);
+		}
+		print sum;
+		`, "5050\n"},
+		{`
+		var count = 0;
+		fun increment(n) {
+			for (var i = 0; i < n; i++) count++;
+		}
+		var tasks = array();
+		for (var i = 0; i < 4; i++) push(tasks, spawn increment(10000));
+		for (var i = 0; i < 4; i++) wait(get(tasks, i));
+		print count;
+		`, "40000\n"},
+		{`
+		fun scoped(n) {
+			var local = n;
+			time.sleep(0.01);
+			return local;
+		}
+		var a = spawn scoped(1);
+		var b = spawn scoped(2);
+		print wait(a) * 10 + wait(b);
+		`, "12\n"},
+		{`
+		var a = chan();
+		var b = chan(1);
+		send(b, "b");
+		print select(array(a, b));
+		print select(array(a), 0.01);
+		`, "[1, \"b\"]\n<nil>\n"},
+	}
+
+	for i, tt := range tests {
+		var output bytes.Buffer
+
+		interpreter := New()
+		interpreter.Stdout = &output
+
+		err := interpret(t, interpreter, tt.source)
+		if err != nil {
+			t.Fatalf("tests[%d] - unexpected error: %v", i, err)
+		}
+
+		if output.String() != tt.expected {
+			t.Fatalf("tests[%d] - output wrong. expected=%q, got=%q", i, tt.expected, output.String())
+		}
+	}
+}
+
+func TestTaskErrors(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	tests := []struct {
+		source        string
+		ctx           context.Context
+		expectedError error
+	}{
+		{"fun f() { while (true) {} } wait(spawn f());", ctx, context.DeadlineExceeded},
+		{"recv(chan());", ctx, context.DeadlineExceeded},
+		{"fun f() { return f(); } wait(spawn f());", nil, ErrCallDepth},
+	}
+
+	for i, tt := range tests {
+		interpreter := New()
+		interpreter.Context = tt.ctx
+
+		err := interpret(t, interpreter, tt.source)
+		if !errors.Is(err, tt.expectedError) {
+			t.Fatalf("tests[%d] - error wrong. expected=%v, got=%v", i, tt.expectedError, err)
+		}
+	}
+
+	interpreter := New()
+	err := interpret(t, interpreter, "var c = chan(1); close(c); send(c, 1);")
+	if err == nil {
+		t.Fatalf("send on a closed channel - expected an error")
+	}
+}
+
+func TestTasksStopped(t *testing.T) {
+	program, err := Compile("fun spin() { while (true) {} } var task = spawn spin();")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	interpreter := New()
+	err = interpreter.Run(program)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the task is stopped once the script ends,
+	// even though its context is never done.
+	task := interpreter.Globals.Values["task"].(*Task)
+	select {
+	case <-task.done:
+	case <-time.After(time.Second):
+		t.Fatalf("task still running")
+	}
+
+	if !errors.Is(task.err, context.Canceled) {
+		t.Fatalf("error wrong. expected=%v, got=%v", context.Canceled, task.err)
+	}
+}
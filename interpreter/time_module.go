@@ -0,0 +1,363 @@
+package interpreter
+
+import (
+	"fmt"
+	"math"
+	"time"
+)
+
+// Durations are numbers of seconds, so that
+// they work with the arithmetic operators.
+
+// Time is an instant in time with a location.
+type Time struct {
+	Time time.Time
+}
+
+func (t *Time) String() string {
+	return t.Time.Format(time.RFC3339Nano)
+}
+
+// start is the reference of the monotonic clock.
+var start = time.Now()
+
+// maxSeconds is the largest number of seconds from the
+// Unix epoch a time can be, about 146 billion years.
+const maxSeconds = 1 << 62
+
+// maxField is the largest year, month, day, hour
+// or minute accepted by date.
+const maxField = 1_000_000_000
+
+// toDuration converts a number of seconds to a duration,
+// saturating at the longest duration, about 292 years.
+func toDuration(seconds float64) time.Duration {
+	d := seconds * float64(time.Second)
+	switch {
+	case math.IsNaN(d):
+		return 0
+	case d >= math.MaxInt64:
+		return math.MaxInt64
+	case d <= math.MinInt64:
+		return math.MinInt64
+	}
+
+	return time.Duration(d)
+}
+
+// addSeconds adds a number of seconds to a time. Unlike
+// time.Time.Add, the seconds are not limited to the range
+// of a duration.
+func addSeconds(function string, t time.Time, seconds float64) (time.Time, error) {
+	whole := math.Floor(seconds)
+	sum := float64(t.Unix()) + whole
+	if math.IsNaN(sum) || math.Abs(sum) > maxSeconds {
+		return time.Time{}, fmt.Errorf("%v: time out of range.", function)
+	}
+
+	nanoseconds := int64(t.Nanosecond()) + int64(math.Round((seconds-whole)*1e9))
+	return time.Unix(t.Unix()+int64(whole), nanoseconds).In(t.Location()), nil
+}
+
+// toSeconds converts a time to a number of
+// seconds from the Unix epoch.
+func toSeconds(t time.Time) float64 {
+	return float64(t.Unix()) + float64(t.Nanosecond())/1e9
+}
+
+// timeArgument returns the argument at index n,
+// checking that it is a time.
+func timeArgument(function string, arguments []any, n int) (time.Time, error) {
+	t, ok := arguments[n].(*Time)
+	if !ok {
+		return time.Time{}, fmt.Errorf("%v: argument %v must be a time.", function, n+1)
+	}
+
+	return t.Time, nil
+}
+
+// layoutArgument returns the optional layout argument
+// at index n, defaulting to RFC 3339.
+func layoutArgument(function string, arguments []any, n int) (string, error) {
+	if len(arguments) <= n {
+		return time.RFC3339, nil
+	}
+
+	return stringArgument(function, arguments, n)
+}
+
+// timeComponent creates a native returning
+// a component of a time.
+func timeComponent(name string, f func(time.Time) int) *NativeFunction {
+	return &NativeFunction{
+		Name:   name,
+		Params: 1,
+		Function: func(interpreter *Interpreter, arguments []any) (any, error) {
+			t, err := timeArgument(name, arguments, 0)
+			if err != nil {
+				return nil, err
+			}
+
+			return int64(f(t)), nil
+		},
+	}
+}
+
+// timeConversion creates a native converting
+// a time to another time.
+func timeConversion(name string, f func(time.Time) time.Time) *NativeFunction {
+	return &NativeFunction{
+		Name:   name,
+		Params: 1,
+		Function: func(interpreter *Interpreter, arguments []any) (any, error) {
+			t, err := timeArgument(name, arguments, 0)
+			if err != nil {
+				return nil, err
+			}
+
+			return &Time{f(t)}, nil
+		},
+	}
+}
+
+// clockNative returns the native measuring elapsed
+// time in seconds with a monotonic clock.
+func clockNative() *NativeFunction {
+	return &NativeFunction{
+		Name:   "clock",
+		Params: 0,
+		Function: func(interpreter *Interpreter, arguments []any) (any, error) {
+			return time.Since(start).Seconds(), nil
+		},
+	}
+}
+
+// newTimeModule creates the time namespace.
+func newTimeModule() *Module {
+	return newNamespace("time", map[string]any{
+		"rfc3339":  time.RFC3339,
+		"rfc1123":  time.RFC1123,
+		"dateTime": time.DateTime,
+		"dateOnly": time.DateOnly,
+		"timeOnly": time.TimeOnly,
+		"kitchen":  time.Kitchen,
+
+		"clock": clockNative(),
+
+		"now": &NativeFunction{
+			Name:   "now",
+			Params: 0,
+			Function: func(interpreter *Interpreter, arguments []any) (any, error) {
+				return &Time{time.Now()}, nil
+			},
+		},
+
+		"sleep": &NativeFunction{
+			Name:   "sleep",
+			Params: 1,
+			Function: func(interpreter *Interpreter, arguments []any) (any, error) {
+				seconds, err := floatArgument("sleep", arguments, 0)
+				if err != nil {
+					return nil, err
+				}
+
+				timer := time.NewTimer(toDuration(seconds))
+				defer timer.Stop()
+
+				interpreter.blocking(func() {
+					select {
+					case <-timer.C:
+					case <-interpreter.context().Done():
+						err = interpreter.stopped()
+					}
+				})
+
+				return nil, err
+			},
+		},
+
+		"date": &NativeFunction{
+			Name:   "date",
+			Params: Variadic,
+			Function: func(interpreter *Interpreter, arguments []any) (any, error) {
+				err := checkArgumentCount("date", arguments, 3, 6)
+				if err != nil {
+					return nil, err
+				}
+
+				// year, month, day, hour and minute.
+				var fields [5]int
+				for n := 0; n < len(fields) && n < len(arguments); n++ {
+					fields[n], err = intArgument("date", arguments, n)
+					if err != nil {
+						return nil, err
+					}
+				}
+
+				var seconds float64
+				if len(arguments) == 6 {
+					seconds, err = floatArgument("date", arguments, 5)
+					if err != nil {
+						return nil, err
+					}
+				}
+
+				// larger fields overflow time.Date.
+				for _, field := range fields {
+					if field < -maxField || field > maxField {
+						return nil, fmt.Errorf("date: %v out of range.", field)
+					}
+				}
+
+				t := time.Date(fields[0], time.Month(fields[1]), fields[2], fields[3], fields[4], 0, 0, time.Local)
+				t, err = addSeconds("date", t, seconds)
+				if err != nil {
+					return nil, err
+				}
+
+				return &Time{t}, nil
+			},
+		},
+
+		"unix": &NativeFunction{
+			Name:   "unix",
+			Params: 1,
+			Function: func(interpreter *Interpreter, arguments []any) (any, error) {
+				t, err := timeArgument("unix", arguments, 0)
+				if err != nil {
+					return nil, err
+				}
+
+				return toSeconds(t), nil
+			},
+		},
+
+		"fromUnix": &NativeFunction{
+			Name:   "fromUnix",
+			Params: 1,
+			Function: func(interpreter *Interpreter, arguments []any) (any, error) {
+				seconds, err := floatArgument("fromUnix", arguments, 0)
+				if err != nil {
+					return nil, err
+				}
+
+				t, err := addSeconds("fromUnix", time.Unix(0, 0), seconds)
+				if err != nil {
+					return nil, err
+				}
+
+				return &Time{t}, nil
+			},
+		},
+
+		"format": &NativeFunction{
+			Name:   "format",
+			Params: Variadic,
+			Function: func(interpreter *Interpreter, arguments []any) (any, error) {
+				err := checkArgumentCount("format", arguments, 1, 2)
+				if err != nil {
+					return nil, err
+				}
+
+				t, err := timeArgument("format", arguments, 0)
+				if err != nil {
+					return nil, err
+				}
+
+				layout, err := layoutArgument("format", arguments, 1)
+				if err != nil {
+					return nil, err
+				}
+
+				return t.Format(layout), nil
+			},
+		},
+
+		"parse": &NativeFunction{
+			Name:   "parse",
+			Params: Variadic,
+			Function: func(interpreter *Interpreter, arguments []any) (any, error) {
+				err := checkArgumentCount("parse", arguments, 1, 2)
+				if err != nil {
+					return nil, err
+				}
+
+				s, err := stringArgument("parse", arguments, 0)
+				if err != nil {
+					return nil, err
+				}
+
+				layout, err := layoutArgument("parse", arguments, 1)
+				if err != nil {
+					return nil, err
+				}
+
+				t, err := time.ParseInLocation(layout, s, time.Local)
+				if err != nil {
+					return nil, fmt.Errorf("parse: %v.", err)
+				}
+
+				return &Time{t}, nil
+			},
+		},
+
+		"add": &NativeFunction{
+			Name:   "add",
+			Params: 2,
+			Function: func(interpreter *Interpreter, arguments []any) (any, error) {
+				t, err := timeArgument("add", arguments, 0)
+				if err != nil {
+					return nil, err
+				}
+
+				seconds, err := floatArgument("add", arguments, 1)
+				if err != nil {
+					return nil, err
+				}
+
+				t, err = addSeconds("add", t, seconds)
+				if err != nil {
+					return nil, err
+				}
+
+				return &Time{t}, nil
+			},
+		},
+
+		"diff": &NativeFunction{
+			Name:   "diff",
+			Params: 2,
+			Function: func(interpreter *Interpreter, arguments []any) (any, error) {
+				a, err := timeArgument("diff", arguments, 0)
+				if err != nil {
+					return nil, err
+				}
+
+				b, err := timeArgument("diff", arguments, 1)
+				if err != nil {
+					return nil, err
+				}
+
+				// a.Sub(b) saturates past about 292 years.
+				return float64(a.Unix()) - float64(b.Unix()) + float64(a.Nanosecond()-b.Nanosecond())/1e9, nil
+			},
+		},
+
+		"year":    timeComponent("year", time.Time.Year),
+		"day":     timeComponent("day", time.Time.Day),
+		"hour":    timeComponent("hour", time.Time.Hour),
+		"minute":  timeComponent("minute", time.Time.Minute),
+		"second":  timeComponent("second", time.Time.Second),
+		"yearDay": timeComponent("yearDay", time.Time.YearDay),
+		"month": timeComponent("month", func(t time.Time) int {
+			return int(t.Month())
+		}),
+		// weekday is 0 for Sunday.
+		"weekday": timeComponent("weekday", func(t time.Time) int {
+			return int(t.Weekday())
+		}),
+
+		"utc":   timeConversion("utc", time.Time.UTC),
+		"local": timeConversion("local", time.Time.Local),
+	})
+}
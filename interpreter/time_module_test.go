@@ -0,0 +1,71 @@
+package interpreter
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestTime(t *testing.T) {
+	tests := []struct {
+		source        string
+		expected      string
+		expectedError bool
+	}{
+		{"print time.format(time.utc(time.fromUnix(0)));", "1970-01-01T00:00:00Z\n", false},
+		{"print time.year(time.utc(time.fromUnix(365 * 86400)));", "1971\n", false},
+		{"print time.unix(time.fromUnix(1.5));", "1.5\n", false},
+		{"var t = time.now(); print time.diff(time.add(t, 90), t);", "90\n", false},
+		{`print time.format(time.utc(time.parse("2024-02-29T12:30:00Z")), time.dateTime);`, "2024-02-29 12:30:00\n", false},
+		{`print time.format(time.parse("2024-02-29", time.dateOnly), time.dateOnly);`, "2024-02-29\n", false},
+		{"print time.format(time.date(2024, 1, 1, 15, 4), time.kitchen);", "3:04PM\n", false},
+
+		// components are in the location of the time.
+		{"var d = time.date(2024, 2, 29, 13, 5, 7.5); print time.year(d) + time.month(d) + time.day(d);", "2055\n", false},
+		{"var d = time.date(2024, 2, 29, 13, 5, 7.5); print time.hour(d) * 10000 + time.minute(d) * 100 + time.second(d);", "130507\n", false},
+		{"var d = time.date(2024, 2, 29); print time.weekday(d);", "4\n", false},
+		{"var d = time.date(2024, 2, 29); print time.yearDay(d);", "60\n", false},
+		{"var d = time.date(2024, 2, 30); print time.month(d) * 100 + time.day(d);", "301\n", false},
+
+		{"var c = time.clock(); time.sleep(0.01); print time.clock() - c >= 0.01;", "true\n", false},
+
+		// times are not limited to the range of a duration.
+		{"print time.year(time.utc(time.fromUnix(10000000000)));", "2286\n", false},
+		{"print time.unix(time.fromUnix(10000000000));", "1e+10\n", false},
+		{"print time.format(time.utc(time.fromUnix(-1.5)), time.dateTime);", "1969-12-31 23:59:58\n", false},
+		{"print time.year(time.add(time.utc(time.fromUnix(946684800)), 10000000000));", "2316\n", false},
+		{"print time.year(time.add(time.utc(time.fromUnix(946684800)), -10000000000));", "1683\n", false},
+		{"print time.year(time.date(100000, 1, 1));", "100000\n", false},
+		{`print time.diff(time.parse("2500-01-01T00:00:00Z"), time.parse("1900-01-01T00:00:00Z"));`, "1.89342144e+10\n", false},
+		{`print time.diff(time.parse("1900-01-01T00:00:00Z"), time.parse("2500-01-01T00:00:00.5Z"));`, "-1.89342144005e+10\n", false},
+		{"time.fromUnix(1e300);", "", true},
+		{"time.fromUnix(-1e300);", "", true},
+		{"time.fromUnix(0/0);", "", true},
+		{"time.add(time.now(), 1e19);", "", true},
+		{"time.date(2000, 1, 1, 0, 0, 1e300);", "", true},
+		{"time.date(10000000000, 1, 1);", "", true},
+
+		{`time.parse("x");`, "", true},
+		{`time.parse("2024", 1);`, "", true},
+		{"time.year(1);", "", true},
+		{"time.date(2024, 1);", "", true},
+		{"time.date(2024, 1, 1.5);", "", true},
+		{`time.sleep("a");`, "", true},
+		{"time.format(time.now(), 1);", "", true},
+	}
+
+	for i, tt := range tests {
+		var output bytes.Buffer
+
+		interpreter := New()
+		interpreter.Stdout = &output
+
+		err := interpret(t, interpreter, tt.source)
+		if (err != nil) != tt.expectedError {
+			t.Fatalf("tests[%d] - error wrong for %v. expected=%v, got=%v", i, tt.source, tt.expectedError, err)
+		}
+
+		if output.String() != tt.expected {
+			t.Fatalf("tests[%d] - output wrong for %v. expected=%q, got=%q", i, tt.source, tt.expected, output.String())
+		}
+	}
+}
@@ -2,12 +2,14 @@ package main
 
 import (
 	"bufio"
+	"context"
+	"errors"
+	"flag"
 	"fmt"
 	"golox/interpreter"
-	"golox/parser"
-	"golox/scanner"
 	"golox/statement"
 	"os"
+	"path/filepath"
 	"time"
 )
 
@@ -53,46 +55,56 @@ func PrintAst(stmt statement.Stmt) {
 
 }
 
-// TODO : move to somewhere else
-type clock struct{}
+// searchPaths contains the directories searched
+// for imported modules.
+var searchPaths []string
 
-func (c *clock) Arity() int {
-	return 0
-}
+// scriptArgs contains the arguments
+// passed to the script.
+var scriptArgs []string
 
-func (c *clock) Call(
-	interpreter interpreter.Interpreter,
-	arguments []any,
-) any {
-	return float64(time.Now().UnixMilli() / 1000)
-}
+// limits restricts the resources used by scripts.
+var limits interpreter.Limits
 
-func (c *clock) ToString() string {
-	return "<native fn>"
-}
+// timeout is the maximum running time of a script.
+var timeout time.Duration
+
+// stdin is shared by the prompt and
+// the scripts reading their input.
+var stdin = bufio.NewReader(os.Stdin)
 
 func main() {
+	path := flag.String(
+		"path",
+		os.Getenv("GOLOX_PATH"),
+		"directories searched for imported modules, separated by the OS path list separator",
+	)
+	flag.DurationVar(&timeout, "timeout", 0, "maximum running time of the script, 0 for no limit")
+	flag.Int64Var(&limits.Steps, "max-steps", 0, "maximum number of statements and expressions evaluated, 0 for no limit")
+	flag.IntVar(&limits.CallDepth, "max-depth", interpreter.DefaultCallDepth, "maximum depth of nested calls, 0 for no limit")
+	flag.Uint64Var(&limits.Memory, "max-memory", 0, "maximum number of bytes allocated, 0 for no limit")
+	flag.Parse()
+
+	searchPaths = filepath.SplitList(*path)
+
 	// get arguments from program
-	args := os.Args
+	args := flag.Args()
 
-	// golox command expects 1 argument
-	// which is the path of the script
-	if len(args) > 2 {
-		fmt.Println("Usage: golox [script]")
-		return
-	} else if len(args) == 2 {
-		runFile(args[1])
+	// golox command expects the path of the script
+	// followed by the arguments passed to the script
+	if len(args) >= 1 {
+		scriptArgs = args[1:]
+		runFile(args[0])
 	} else {
 		runPromt()
 	}
 }
 
 func runPromt() {
-	reader := bufio.NewReader(os.Stdin)
 	for {
 		fmt.Print("> ")
 
-		text, err := reader.ReadString('\n')
+		text, err := stdin.ReadString('\n')
 		if err != nil {
 			fmt.Println(err)
 		}
@@ -101,7 +113,11 @@ func runPromt() {
 			break
 		}
 
-		run(text)
+		// an error only ends the line that caused
+		// it, unless the line calls exit.
+		err = run(text, "")
+		checkExit(err)
+		report(err)
 	}
 }
 
@@ -109,37 +125,71 @@ func runFile(path string) {
 	data, err := os.ReadFile(path)
 	if err != nil {
 		fmt.Println(err)
+		os.Exit(1)
 	}
 
-	run(string(data))
+	err = run(string(data), path)
+	checkExit(err)
+	if err != nil {
+		report(err)
+		os.Exit(1)
+	}
 }
 
-func run(source string) {
-	scanner := scanner.New(source)
-	tokens := scanner.ScanTokens()
+// checkExit exits with the code passed to
+// exit if err was returned by a script calling it.
+func checkExit(err error) {
+	var exit *interpreter.ExitError
+	if errors.As(err, &exit) {
+		os.Exit(exit.Code)
+	}
+}
 
-	parser := parser.Parser{
-		Tokens: tokens,
+// report prints an error returned by run. Compile
+// errors are not printed, as they are reported
+// while the source is compiled.
+func report(err error) {
+	if err != nil && !errors.Is(err, interpreter.ErrCompile) {
+		fmt.Println(err)
 	}
-	statements, isError := parser.Parse()
-	if isError {
-		os.Exit(1)
+}
+
+// run runs a golox source read from the script at
+// path, or from the prompt if path is empty, and
+// returns the error that stopped it, if any.
+func run(source string, path string) error {
+	program, err := interpreter.Compile(source)
+	if err != nil {
+		return err
 	}
 
-	// initialize global environment here for
-	// a fixed reference to the outermost global
-	// environment for the interpreter.
-	globalEnv := interpreter.Environment{
-		Enclosing: nil,
-		Values:    make(map[string]any),
+	// scripts run from the command line can access
+	// every file and run every command the user can.
+	files := &interpreter.FileAccess{}
+
+	interpreter := interpreter.New()
+	interpreter.Loader.SearchPaths = searchPaths
+	interpreter.Files = files
+	interpreter.Process = true
+	interpreter.Args = scriptArgs
+	interpreter.Stdin = stdin
+	interpreter.Limits = limits
+
+	if timeout > 0 {
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		defer cancel()
+
+		interpreter.Context = ctx
 	}
 
-	globalEnv.Define("clock", clock{})
+	if path != "" {
+		interpreter.Dir = filepath.Dir(path)
 
-	interpreter := interpreter.Interpreter{
-		Environment: globalEnv,
-		Globals:     globalEnv,
+		err := interpreter.Enter(path)
+		if err != nil {
+			return err
+		}
 	}
 
-	interpreter.Interpret(statements)
+	return interpreter.Run(program)
 }
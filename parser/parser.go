@@ -15,18 +15,64 @@ for golox
 
 expression     → assignment ;
 
-assignment     → IDENTIFIER "=" assignment | logic_or;
+assignment     → IDENTIFIER ( "=" | "+=" | "-=" | "*=" | "/=" | "%=" )
+                 assignment | conditional;
+
+conditional    → coalesce ( "?" expression ":" conditional )? ;
+coalesce       → logic_or ( "??" logic_or )* ;
 
 logic_or       → logic_and ( "or" logic_and )* ;
 logic_and      → equality ( "and" equality )* ;
 equality       → comparison ( ( "!=" | "==" ) comparison )* ;
-comparison     → term ( ( ">" | ">=" | "<" | "<=" ) term )* ;
+comparison     → bit_or ( ( ">" | ">=" | "<" | "<=" ) bit_or )* ;
+bit_or         → bit_xor ( "|" bit_xor )* ;
+bit_xor        → bit_and ( "^" bit_and )* ;
+bit_and        → shift ( "&" shift )* ;
+shift          → term ( ( "<<" | ">>" ) term )* ;
 term           → factor ( ( "-" | "+" ) factor )* ;
-factor         → unary ( ( "/" | "*" ) unary )* ;
-unary          → ( "!" | "-" ) unary
-               | primary ;
+factor         → unary ( ( "/" | "*" | "~/" | "%" ) unary )* ;
+unary          → ( "!" | "-" | "~" ) unary
+               | ( "++" | "--" ) IDENTIFIER
+               | "spawn" call
+               | power ;
+power          → postfix ( "**" unary )? ;
+postfix        → call ( "++" | "--" )? ;
+call           → primary ( "(" arguments? ")" | "." IDENTIFIER )* ;
+arguments      → argument ( "," argument )* ;
+argument       → ( IDENTIFIER ":" )? expression ;
 primary        → NUMBER | STRING | "true" | "false" | "nil"
                | "(" expression ")" ;
+
+Operator precedence, from lowest to highest :
+
+  ? :                    right
+  ??                     left
+  or                     left
+  and                    left
+  == !=                  left
+  < <= > >=              left
+  |                      left
+  ^                      left
+  &                      left
+  << >>                  left
+  + -                    left
+  * / ~/ %               left
+  ! - ~ ++ -- spawn      right
+  **                     right
+  ++ -- (postfix)        left
+  () (call)              left
+
+"++" and "--" are scanned as single tokens, as in C, so
+5--3 is an invalid decrement of 5 and a subtraction of a
+negative number is written with a space, as in 5 - -3.
+
+"~/" is the integer division, which rounds towards negative
+infinity. It is not spelled "//" because "//" starts a line
+comment.
+
+"**" binds tighter than unary operators on its left
+but not on its right, so -2 ** 2 is -(2 ** 2) and
+2 ** -1 is 2 ** (-1).
 */
 
 // Parser represents a parser object.
@@ -35,6 +81,42 @@ primary        → NUMBER | STRING | "true" | "false" | "nil"
 type Parser struct {
 	Tokens  []token.Token
 	Current int
+
+	// MaxParameters and MaxArguments are the maximum
+	// number of parameters of a function and of
+	// arguments of a call. Zero or a negative value
+	// means DefaultMax.
+	MaxParameters int
+	MaxArguments  int
+
+	// yields is set when the function being
+	// parsed contains a yield statement.
+	yields bool
+
+	// hadError is set when an error is reported
+	// without stopping the parsing.
+	hadError bool
+}
+
+// DefaultMax is the default maximum number of
+// parameters of a function and of arguments of a call.
+const DefaultMax = 255
+
+// limit returns max, or DefaultMax if max is zero
+// or negative.
+func limit(max int) int {
+	if max <= 0 {
+		return DefaultMax
+	}
+
+	return max
+}
+
+// error reports an error at the given token
+// without stopping the parsing.
+func (p *Parser) error(tk token.Token, message string) {
+	p.hadError = true
+	errorx.Report(tk.Line, fmt.Sprintf(" at '%v'", tk.Lexeme), message)
 }
 
 // Previous returns the previous token.
@@ -97,6 +179,17 @@ func (p *Parser) consume(tp token.TokenType, message string) (token.Token, error
 	return p.peek(), errors.New(message)
 }
 
+// consumeContextual consumes an identifier that acts as
+// a keyword in some places, such as "as" in imports.
+func (p *Parser) consumeContextual(keyword string, message string) error {
+	if p.check(token.IDENTIFIER) && p.peek().Lexeme == keyword {
+		p.advance()
+		return nil
+	}
+
+	return errors.New(message)
+}
+
 // synchronize unwinds the parser by discarding tokens.
 func (p *Parser) synchronize() {
 	p.advance()
@@ -110,6 +203,7 @@ func (p *Parser) synchronize() {
 		case token.CLASS:
 		case token.FUN:
 		case token.VAR:
+		case token.LET:
 		case token.FOR:
 		case token.IF:
 		case token.WHILE:
@@ -155,7 +249,7 @@ func (p *Parser) equality() (ast.Expr, error) {
 // expression contains a comparison with >, >=, <, <=.
 func (p *Parser) comparison() (ast.Expr, error) {
 	var err error
-	expr, err := p.term()
+	expr, err := p.bitOr()
 
 	for p.match(
 		token.GREATER,
@@ -164,7 +258,7 @@ func (p *Parser) comparison() (ast.Expr, error) {
 		token.LESS_EQUAL,
 	) {
 		operator := p.previous()
-		right, err := p.term()
+		right, err := p.bitOr()
 		if err != nil {
 			return nil, err
 		}
@@ -179,6 +273,106 @@ func (p *Parser) comparison() (ast.Expr, error) {
 	return expr, err
 }
 
+// bitOr parses a bitwise or expression.
+func (p *Parser) bitOr() (ast.Expr, error) {
+	expr, err := p.bitXor()
+	if err != nil {
+		return nil, err
+	}
+
+	for p.match(token.PIPE) {
+		operator := p.previous()
+		right, err := p.bitXor()
+		if err != nil {
+			return nil, err
+		}
+
+		expr = &ast.Binary{
+			Left:     expr,
+			Operator: operator,
+			Right:    right,
+		}
+	}
+
+	return expr, nil
+}
+
+// bitXor parses a bitwise xor expression.
+func (p *Parser) bitXor() (ast.Expr, error) {
+	expr, err := p.bitAnd()
+	if err != nil {
+		return nil, err
+	}
+
+	for p.match(token.CARET) {
+		operator := p.previous()
+		right, err := p.bitAnd()
+		if err != nil {
+			return nil, err
+		}
+
+		expr = &ast.Binary{
+			Left:     expr,
+			Operator: operator,
+			Right:    right,
+		}
+	}
+
+	return expr, nil
+}
+
+// bitAnd parses a bitwise and expression.
+func (p *Parser) bitAnd() (ast.Expr, error) {
+	expr, err := p.shift()
+	if err != nil {
+		return nil, err
+	}
+
+	for p.match(token.AMPERSAND) {
+		operator := p.previous()
+		right, err := p.shift()
+		if err != nil {
+			return nil, err
+		}
+
+		expr = &ast.Binary{
+			Left:     expr,
+			Operator: operator,
+			Right:    right,
+		}
+	}
+
+	return expr, nil
+}
+
+// shift parses a shift expression. A shift
+// expression contains a left or right shift.
+func (p *Parser) shift() (ast.Expr, error) {
+	expr, err := p.term()
+	if err != nil {
+		return nil, err
+	}
+
+	for p.match(
+		token.LESS_LESS,
+		token.GREATER_GREATER,
+	) {
+		operator := p.previous()
+		right, err := p.term()
+		if err != nil {
+			return nil, err
+		}
+
+		expr = &ast.Binary{
+			Left:     expr,
+			Operator: operator,
+			Right:    right,
+		}
+	}
+
+	return expr, nil
+}
+
 // comparison parses a term expression. A term
 // expression contains addition or subtraction.
 func (p *Parser) term() (ast.Expr, error) {
@@ -206,7 +400,8 @@ func (p *Parser) term() (ast.Expr, error) {
 }
 
 // factor parses a factor expression. A factor
-// expression contains multiplication and division.
+// expression contains multiplication, division,
+// integer division and modulo.
 func (p *Parser) factor() (ast.Expr, error) {
 	var err error
 	expr, err := p.unary()
@@ -217,6 +412,8 @@ func (p *Parser) factor() (ast.Expr, error) {
 	for p.match(
 		token.STAR,
 		token.SLASH,
+		token.TILDE_SLASH,
+		token.PERCENT,
 	) {
 		operator := p.previous()
 		right, err := p.unary()
@@ -235,30 +432,48 @@ func (p *Parser) factor() (ast.Expr, error) {
 }
 
 // finishCall parses each of the arguments to a function
-// and includes it in the call node.
+// and includes it in the call node. Named arguments must
+// follow the positional ones.
 func (p *Parser) finishCall(callee ast.Expr) (ast.Expr, error) {
 	arguments := []ast.Expr{}
+	named := []ast.NamedArgument{}
 	if !p.check(token.RIGHT_PAREN) {
-		if len(arguments) >= 255 {
-			return nil, errors.New("Can't have more than 255 arguments.")
-		}
-
-		// get first argument
-		expr, err := p.expression()
-		if err != nil {
-			return nil, err
-		}
-
-		arguments = append(arguments, expr)
+		for {
+			// the error is reported once, at
+			// the first argument over the limit.
+			if max := limit(p.MaxArguments); len(arguments)+len(named) == max {
+				p.error(p.peek(), fmt.Sprintf("Can't have more than %v arguments.", max))
+			}
 
-		// get next arguments
-		for p.match(token.COMMA) {
-			expr, err := p.expression()
-			if err != nil {
-				return nil, err
+			if p.isNamedArgument() {
+				name := p.advance()
+				p.advance()
+
+				value, err := p.expression()
+				if err != nil {
+					return nil, err
+				}
+
+				named = append(named, ast.NamedArgument{
+					Name:  name,
+					Value: value,
+				})
+			} else {
+				if len(named) > 0 {
+					return nil, errors.New("Expect named argument after named arguments.")
+				}
+
+				expr, err := p.expression()
+				if err != nil {
+					return nil, err
+				}
+
+				arguments = append(arguments, expr)
 			}
 
-			arguments = append(arguments, expr)
+			if !p.match(token.COMMA) {
+				break
+			}
 		}
 	}
 
@@ -271,12 +486,22 @@ func (p *Parser) finishCall(callee ast.Expr) (ast.Expr, error) {
 		Callee:    callee,
 		Paren:     paren,
 		Arguments: arguments,
+		Named:     named,
 	}, nil
 }
 
+// isNamedArgument checks if the next argument of a
+// call starts with a parameter name followed by ':'.
+func (p *Parser) isNamedArgument() bool {
+	n := p.Current
+	return n+1 < len(p.Tokens) &&
+		p.Tokens[n].Type == token.IDENTIFIER &&
+		p.Tokens[n+1].Type == token.COLON
+}
+
 // call parses a function call, determines the callee, and
 // calls finishCall() to construct the nodes for a
-// function call.
+// function call. It also parses property accesses.
 func (p *Parser) call() (ast.Expr, error) {
 	expr, err := p.primary()
 	if err != nil {
@@ -289,6 +514,16 @@ func (p *Parser) call() (ast.Expr, error) {
 			if err != nil {
 				return nil, err
 			}
+		} else if p.match(token.DOT) {
+			name, err := p.consume(token.IDENTIFIER, "Expect property name after '.'.")
+			if err != nil {
+				return nil, err
+			}
+
+			expr = &ast.Get{
+				Object: expr,
+				Name:   name,
+			}
 		} else {
 			break
 		}
@@ -298,11 +533,13 @@ func (p *Parser) call() (ast.Expr, error) {
 }
 
 // unary parses a unary expression. A unary
-// expression contains negation (! or -).
+// expression contains negation (! or -) or
+// a bitwise complement (~).
 func (p *Parser) unary() (ast.Expr, error) {
 	for p.match(
 		token.BANG,
 		token.MINUS,
+		token.TILDE,
 	) {
 		operator := p.previous()
 		right, err := p.unary()
@@ -312,7 +549,125 @@ func (p *Parser) unary() (ast.Expr, error) {
 		}, err
 	}
 
-	return p.call()
+	// prefix increments are desugared into
+	// "a = a + 1", producing the new value.
+	if p.match(
+		token.PLUS_PLUS,
+		token.MINUS_MINUS,
+	) {
+		operator := p.previous()
+		right, err := p.unary()
+		if err != nil {
+			return nil, err
+		}
+
+		// the error is reported without stopping the
+		// parsing, as the parser is not confused.
+		v, ok := right.(*ast.Variable)
+		if !ok {
+			p.error(operator, fmt.Sprintf("Invalid %v target.", operator.Lexeme))
+			return right, nil
+		}
+
+		binaryOperator := token.Token{
+			Type:   token.PLUS,
+			Lexeme: "+",
+			Line:   operator.Line,
+		}
+		if operator.Type == token.MINUS_MINUS {
+			binaryOperator.Type = token.MINUS
+			binaryOperator.Lexeme = "-"
+		}
+
+		return &ast.Assign{
+			Name: v.Name,
+			Value: &ast.Binary{
+				Left:     v,
+				Operator: binaryOperator,
+				Right: &ast.Literal{
+					Value: int64(1),
+				},
+			},
+		}, nil
+	}
+
+	// spawn applies to a whole call, so that the
+	// callee and the arguments are evaluated
+	// before the task starts.
+	if p.match(token.SPAWN) {
+		keyword := p.previous()
+		expr, err := p.call()
+		if err != nil {
+			return nil, err
+		}
+
+		call, ok := expr.(*ast.Call)
+		if !ok {
+			return nil, errors.New("Expect function call after 'spawn'.")
+		}
+
+		return &ast.Spawn{
+			Keyword: keyword,
+			Call:    call,
+		}, nil
+	}
+
+	return p.power()
+}
+
+// power parses an exponentiation expression. The
+// exponent is parsed as a unary expression, which
+// makes "**" right-associative.
+func (p *Parser) power() (ast.Expr, error) {
+	expr, err := p.postfix()
+	if err != nil {
+		return nil, err
+	}
+
+	if p.match(token.STAR_STAR) {
+		operator := p.previous()
+		right, err := p.unary()
+		if err != nil {
+			return nil, err
+		}
+
+		expr = &ast.Binary{
+			Left:     expr,
+			Operator: operator,
+			Right:    right,
+		}
+	}
+
+	return expr, nil
+}
+
+// postfix parses a postfix increment or decrement,
+// which produces the value from before the update.
+func (p *Parser) postfix() (ast.Expr, error) {
+	expr, err := p.call()
+	if err != nil {
+		return nil, err
+	}
+
+	if p.match(
+		token.PLUS_PLUS,
+		token.MINUS_MINUS,
+	) {
+		operator := p.previous()
+
+		v, ok := expr.(*ast.Variable)
+		if !ok {
+			p.error(operator, fmt.Sprintf("Invalid %v target.", operator.Lexeme))
+			return expr, nil
+		}
+
+		return &ast.Postfix{
+			Name:     v.Name,
+			Operator: operator,
+		}, nil
+	}
+
+	return expr, nil
 }
 
 // primary parses a primary expression. A primary
@@ -501,9 +856,13 @@ func (p *Parser) forStatement() (statement.Stmt, error) {
 		return nil, err
 	}
 
+	if p.isForIn() {
+		return p.forInStatement()
+	}
+
 	if p.match(token.SEMICOLON) {
 		initializer = nil
-	} else if p.match(token.VAR) {
+	} else if p.match(token.VAR, token.LET) {
 		initializer, err = p.varDeclaration()
 		if err != nil {
 			return nil, err
@@ -521,11 +880,11 @@ func (p *Parser) forStatement() (statement.Stmt, error) {
 		if err != nil {
 			return nil, err
 		}
+	}
 
-		_, err = p.consume(token.SEMICOLON, "Expect ';' after loop condition.")
-		if err != nil {
-			return nil, err
-		}
+	_, err = p.consume(token.SEMICOLON, "Expect ';' after loop condition.")
+	if err != nil {
+		return nil, err
 	}
 
 	var increment ast.Expr = nil
@@ -534,27 +893,16 @@ func (p *Parser) forStatement() (statement.Stmt, error) {
 		if err != nil {
 			return nil, err
 		}
-
-		_, err = p.consume(token.RIGHT_PAREN, "Expect ')' after for clauses.")
-		if err != nil {
-			return nil, err
-		}
 	}
 
-	body, err := p.statement()
+	_, err = p.consume(token.RIGHT_PAREN, "Expect ')' after for clauses.")
 	if err != nil {
 		return nil, err
 	}
 
-	if increment != nil {
-		body = &statement.Block{
-			Statements: []statement.Stmt{
-				body,
-				&statement.Expression{
-					Expression: increment,
-				},
-			},
-		}
+	body, err := p.statement()
+	if err != nil {
+		return nil, err
 	}
 
 	if condition == nil {
@@ -563,9 +911,12 @@ func (p *Parser) forStatement() (statement.Stmt, error) {
 		}
 	}
 
+	// the increment is kept apart from the body
+	// so that continue does not skip it.
 	body = &statement.While{
 		Condition: condition,
 		Body:      body,
+		Increment: increment,
 	}
 
 	if initializer != nil {
@@ -580,6 +931,78 @@ func (p *Parser) forStatement() (statement.Stmt, error) {
 	return body, nil
 }
 
+// isForIn checks if the clauses of a for statement
+// start with a loop variable followed by "in".
+func (p *Parser) isForIn() bool {
+	n := p.Current
+	if p.Tokens[n].Type == token.VAR || p.Tokens[n].Type == token.LET {
+		n++
+	}
+
+	return n+1 < len(p.Tokens) &&
+		p.Tokens[n].Type == token.IDENTIFIER &&
+		p.Tokens[n+1].Type == token.IDENTIFIER &&
+		p.Tokens[n+1].Lexeme == "in"
+}
+
+// forInStatement parses the clauses and the body of a
+// for-in statement. The loop variable is declared in
+// a new scope for each iteration, with or without var.
+func (p *Parser) forInStatement() (statement.Stmt, error) {
+	p.match(token.VAR, token.LET)
+
+	name, err := p.consume(token.IDENTIFIER, "Expect loop variable name.")
+	if err != nil {
+		return nil, err
+	}
+
+	err = p.consumeContextual("in", "Expect 'in' after loop variable.")
+	if err != nil {
+		return nil, err
+	}
+
+	iterable, err := p.expression()
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = p.consume(token.RIGHT_PAREN, "Expect ')' after for clauses.")
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := p.statement()
+	if err != nil {
+		return nil, err
+	}
+
+	return &statement.ForIn{
+		Name:     name,
+		Iterable: iterable,
+		Body:     body,
+	}, nil
+}
+
+// jumpStatement parses a break or a continue statement.
+func (p *Parser) jumpStatement() (statement.Stmt, error) {
+	keyword := p.previous()
+
+	_, err := p.consume(token.SEMICOLON, fmt.Sprintf("Expect ';' after '%v'.", keyword.Lexeme))
+	if err != nil {
+		return nil, err
+	}
+
+	if keyword.Type == token.BREAK {
+		return &statement.Break{
+			Keyword: keyword,
+		}, nil
+	}
+
+	return &statement.Continue{
+		Keyword: keyword,
+	}, nil
+}
+
 // statement parses statements.
 func (p *Parser) statement() (statement.Stmt, error) {
 	if p.match(token.FOR) {
@@ -598,6 +1021,14 @@ func (p *Parser) statement() (statement.Stmt, error) {
 		return p.returnStatement()
 	}
 
+	if p.match(token.YIELD) {
+		return p.yieldStatement()
+	}
+
+	if p.match(token.BREAK, token.CONTINUE) {
+		return p.jumpStatement()
+	}
+
 	if p.match(token.WHILE) {
 		return p.whileStatement()
 	}
@@ -664,41 +1095,135 @@ func (p *Parser) or() (ast.Expr, error) {
 	return expr, err
 }
 
-// assignment parses assignment expressions.
-func (p *Parser) assignment() (ast.Expr, error) {
+// conditional parses a conditional expression. The
+// else branch is parsed as another conditional, which
+// makes the operator right-associative.
+func (p *Parser) conditional() (ast.Expr, error) {
+	expr, err := p.coalesce()
+	if err != nil {
+		return nil, err
+	}
+
+	if p.match(token.QUESTION) {
+		thenBranch, err := p.expression()
+		if err != nil {
+			return nil, err
+		}
+
+		_, err = p.consume(token.COLON, "Expect ':' after then branch of conditional expression.")
+		if err != nil {
+			return nil, err
+		}
+
+		elseBranch, err := p.conditional()
+		if err != nil {
+			return nil, err
+		}
+
+		expr = &ast.Conditional{
+			Condition:  expr,
+			ThenBranch: thenBranch,
+			ElseBranch: elseBranch,
+		}
+	}
+
+	return expr, nil
+}
+
+// coalesce parses null-coalescing expressions.
+func (p *Parser) coalesce() (ast.Expr, error) {
 	expr, err := p.or()
 	if err != nil {
 		return nil, err
 	}
 
-	if p.match(token.EQUAL) {
+	for p.match(token.QUESTION_QUESTION) {
+		operator := p.previous()
+		right, err := p.or()
+		if err != nil {
+			return nil, err
+		}
+
+		expr = &ast.Coalesce{
+			Left:     expr,
+			Operator: operator,
+			Right:    right,
+		}
+	}
+
+	return expr, nil
+}
+
+// compoundOperators maps compound assignment operators
+// to the binary operator they apply.
+var compoundOperators = map[token.TokenType]token.TokenType{
+	token.PLUS_EQUAL:    token.PLUS,
+	token.MINUS_EQUAL:   token.MINUS,
+	token.STAR_EQUAL:    token.STAR,
+	token.SLASH_EQUAL:   token.SLASH,
+	token.PERCENT_EQUAL: token.PERCENT,
+}
+
+// assignment parses assignment expressions. Compound
+// assignments such as "a += b" are desugared into
+// "a = a + b".
+func (p *Parser) assignment() (ast.Expr, error) {
+	expr, err := p.conditional()
+	if err != nil {
+		return nil, err
+	}
+
+	if p.match(
+		token.EQUAL,
+		token.PLUS_EQUAL,
+		token.MINUS_EQUAL,
+		token.STAR_EQUAL,
+		token.SLASH_EQUAL,
+		token.PERCENT_EQUAL,
+	) {
 		equals := p.previous()
 		value, err := p.assignment()
 		if err != nil {
 			return nil, err
 		}
 
-		if v, ok := expr.(*ast.Variable); ok {
-			name := v.Name
-			return &ast.Assign{
-				Name:  name,
-				Value: value,
-			}, nil
+		v, ok := expr.(*ast.Variable)
+		if !ok {
+			p.error(equals, "Invalid assignment target.")
+			return expr, nil
+		}
+
+		if operator, ok := compoundOperators[equals.Type]; ok {
+			value = &ast.Binary{
+				Left: v,
+				Operator: token.Token{
+					Type:   operator,
+					Lexeme: string(operator),
+					Line:   equals.Line,
+				},
+				Right: value,
+			}
 		}
 
-		return nil, errors.New(fmt.Sprint(equals) + "Invalid assignment target. ")
+		return &ast.Assign{
+			Name:  v.Name,
+			Value: value,
+		}, nil
 	}
 
 	return expr, nil
 }
 
-// varDeclaration parses variable declarations.
+// varDeclaration parses variable declarations,
+// declared with either "var" or "let".
 func (p *Parser) varDeclaration() (statement.Stmt, error) {
 	var (
 		initializer ast.Expr
 		err         error
 	)
 
+	keyword := p.previous()
+
 	name, err := p.consume(token.IDENTIFIER, "Expect variable name.")
 	if err != nil {
 		return nil, err
@@ -719,6 +1244,37 @@ func (p *Parser) varDeclaration() (statement.Stmt, error) {
 	return &statement.Variable{
 		Name:        name,
 		Initializer: initializer,
+		Let:         keyword.Type == token.LET,
+	}, nil
+}
+
+// constDeclaration parses constant declarations. Unlike
+// variables, constants must have an initializer.
+func (p *Parser) constDeclaration() (statement.Stmt, error) {
+	name, err := p.consume(token.IDENTIFIER, "Expect constant name.")
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = p.consume(token.EQUAL, "Expect '=' after constant name.")
+	if err != nil {
+		return nil, err
+	}
+
+	initializer, err := p.expression()
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = p.consume(token.SEMICOLON, "Expect ';' after constant declaration")
+	if err != nil {
+		return nil, err
+	}
+
+	return &statement.Variable{
+		Name:        name,
+		Initializer: initializer,
+		Constant:    true,
 	}, nil
 }
 
@@ -735,25 +1291,39 @@ func (p *Parser) function(kind string) (*statement.Function, error) {
 	}
 
 	var parameters []token.Token
+	var defaults []ast.Expr
+	rest := false
 	if !p.check(token.RIGHT_PAREN) {
-		if len(parameters) >= 255 {
-			return nil, errors.New("Can't have more than 255 parameters.")
-		}
-
-		param, err := p.consume(token.IDENTIFIER, "Expect parameter name.")
-		if err != nil {
-			return nil, err
-		}
+		for {
+			if max := limit(p.MaxParameters); len(parameters) == max {
+				p.error(p.peek(), fmt.Sprintf("Can't have more than %v parameters.", max))
+			}
 
-		parameters = append(parameters, param)
+			rest = p.match(token.DOT_DOT_DOT)
 
-		for p.match(token.COMMA) {
 			param, err := p.consume(token.IDENTIFIER, "Expect parameter name.")
 			if err != nil {
 				return nil, err
 			}
 
 			parameters = append(parameters, param)
+
+			var value ast.Expr
+			if !rest && p.match(token.EQUAL) {
+				value, err = p.expression()
+				if err != nil {
+					return nil, err
+				}
+			} else if !rest && len(defaults) > 0 && defaults[len(defaults)-1] != nil {
+				return nil, errors.New("Expect default value after parameters with default values.")
+			}
+
+			defaults = append(defaults, value)
+
+			// the rest parameter comes last.
+			if rest || !p.match(token.COMMA) {
+				break
+			}
 		}
 	}
 
@@ -767,15 +1337,25 @@ func (p *Parser) function(kind string) (*statement.Function, error) {
 		return nil, err
 	}
 
+	enclosingYields := p.yields
+	p.yields = false
+
 	body, err := p.block()
+
+	generator := p.yields
+	p.yields = enclosingYields
+
 	if err != nil {
 		return nil, err
 	}
 
 	return &statement.Function{
-		Name:   name,
-		Params: parameters,
-		Body:   body,
+		Name:      name,
+		Params:    parameters,
+		Body:      body,
+		Defaults:  defaults,
+		Rest:      rest,
+		Generator: generator,
 	}, nil
 }
 
@@ -785,13 +1365,137 @@ func (p *Parser) declaration() (statement.Stmt, error) {
 		return p.function("function")
 	}
 
-	if p.match(token.VAR) {
+	if p.match(token.VAR, token.LET) {
 		return p.varDeclaration()
 	}
 
+	if p.match(token.CONST) {
+		return p.constDeclaration()
+	}
+
+	if p.match(token.IMPORT) {
+		return p.importDeclaration()
+	}
+
+	if p.match(token.EXPORT) {
+		return p.exportDeclaration()
+	}
+
 	return p.statement()
 }
 
+// importDeclaration parses import declarations. A module
+// is either imported as a whole and bound to a name, or
+// some of its exports are imported by name.
+func (p *Parser) importDeclaration() (statement.Stmt, error) {
+	keyword := p.previous()
+
+	if p.match(token.LEFT_BRACE) {
+		var names []token.Token
+
+		for {
+			name, err := p.consume(token.IDENTIFIER, "Expect name to import.")
+			if err != nil {
+				return nil, err
+			}
+
+			names = append(names, name)
+
+			if !p.match(token.COMMA) {
+				break
+			}
+		}
+
+		_, err := p.consume(token.RIGHT_BRACE, "Expect '}' after imported names.")
+		if err != nil {
+			return nil, err
+		}
+
+		err = p.consumeContextual("from", "Expect 'from' after imported names.")
+		if err != nil {
+			return nil, err
+		}
+
+		path, err := p.consume(token.STRING, "Expect module path.")
+		if err != nil {
+			return nil, err
+		}
+
+		_, err = p.consume(token.SEMICOLON, "Expect ';' after import.")
+		if err != nil {
+			return nil, err
+		}
+
+		return &statement.Import{
+			Keyword: keyword,
+			Path:    path,
+			Names:   names,
+		}, nil
+	}
+
+	path, err := p.consume(token.STRING, "Expect module path.")
+	if err != nil {
+		return nil, err
+	}
+
+	err = p.consumeContextual("as", "Expect 'as' after module path.")
+	if err != nil {
+		return nil, err
+	}
+
+	alias, err := p.consume(token.IDENTIFIER, "Expect module name.")
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = p.consume(token.SEMICOLON, "Expect ';' after import.")
+	if err != nil {
+		return nil, err
+	}
+
+	return &statement.Import{
+		Keyword: keyword,
+		Path:    path,
+		Alias:   alias,
+	}, nil
+}
+
+// exportDeclaration parses export declarations.
+func (p *Parser) exportDeclaration() (statement.Stmt, error) {
+	var declaration statement.Stmt
+	var name token.Token
+	var err error
+
+	if p.match(token.FUN) {
+		var function *statement.Function
+		function, err = p.function("function")
+		if err == nil {
+			declaration, name = function, function.Name
+		}
+	} else if p.match(token.VAR, token.LET, token.CONST) {
+		if p.previous().Type != token.CONST {
+			declaration, err = p.varDeclaration()
+		} else {
+			declaration, err = p.constDeclaration()
+		}
+
+		if err == nil {
+			name = declaration.(*statement.Variable).Name
+		}
+	} else {
+		return nil, errors.New("Expect declaration after 'export'.")
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &statement.Export{
+		Declaration: declaration,
+		Name:        name,
+	}, nil
+}
+
 // returnStatement parses return statements.
 func (p *Parser) returnStatement() (statement.Stmt, error) {
 	keyword := p.previous()
@@ -815,6 +1519,32 @@ func (p *Parser) returnStatement() (statement.Stmt, error) {
 	}, nil
 }
 
+// yieldStatement parses a yield statement, which
+// makes the enclosing function a generator.
+func (p *Parser) yieldStatement() (statement.Stmt, error) {
+	keyword := p.previous()
+	p.yields = true
+
+	var value ast.Expr = nil
+	var err error
+	if !p.check(token.SEMICOLON) {
+		value, err = p.expression()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	_, err = p.consume(token.SEMICOLON, "Expect ';' after yield value.")
+	if err != nil {
+		return nil, err
+	}
+
+	return &statement.Yield{
+		Keyword: keyword,
+		Value:   value,
+	}, nil
+}
+
 // parse parses the tokens inside the token list.
 func (p *Parser) Parse() ([]statement.Stmt, bool) {
 
@@ -833,5 +1563,5 @@ func (p *Parser) Parse() ([]statement.Stmt, bool) {
 		statements = append(statements, statement)
 	}
 
-	return statements, isError
+	return statements, isError || p.hadError
 }
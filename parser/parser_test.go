@@ -0,0 +1,243 @@
+package parser
+
+import (
+	"fmt"
+	"golox/ast"
+	"golox/scanner"
+	"golox/statement"
+	"strings"
+	"testing"
+)
+
+func TestFor(t *testing.T) {
+	tests := []struct {
+		input         string
+		expectedError bool
+	}{
+		{"for (var i = 0; i < 3; i++) print i;", false},
+		{"for (;;) print 1;", false},
+		{"for (var i = 0;;) print i;", false},
+		{"for (; i < 3;) print i;", false},
+		{"for (;; i++) print i;", false},
+		{"for (;) print 1;", true},
+		{"for (; i < 3) print i;", true},
+		{"for (;; i++ print i;", true},
+	}
+
+	for i, tt := range tests {
+		scanner := scanner.New(tt.input)
+		parser := Parser{
+			Tokens: scanner.ScanTokens(),
+		}
+
+		statements, isError := parser.Parse()
+		if isError != tt.expectedError {
+			t.Fatalf("tests[%d] - error wrong for %v. expected=%v", i, tt.input, tt.expectedError)
+		}
+
+		if tt.expectedError {
+			continue
+		}
+
+		if len(statements) != 1 {
+			t.Fatalf("tests[%d] - statements wrong. expected=1, got=%v", i, len(statements))
+		}
+
+		// a for statement is desugared into a while
+		// statement, in a block with the initializer.
+		stmt := statements[0]
+		if block, ok := stmt.(*statement.Block); ok {
+			stmt = block.Statements[len(block.Statements)-1]
+		}
+
+		if _, ok := stmt.(*statement.While); !ok {
+			t.Fatalf("tests[%d] - statement wrong. expected=*statement.While, got=%T", i, stmt)
+		}
+	}
+}
+
+func TestInvalidTargets(t *testing.T) {
+	tests := []struct {
+		input         string
+		expectedError bool
+	}{
+		{"a = 1;", false},
+		{"a += 1;", false},
+		{"a++; --a;", false},
+		{"5 - -3;", false},
+		{"1 = 2;", true},
+		{"(a) += 1;", true},
+		{"++1;", true},
+		{"a()--;", true},
+		{"5--3;", true},
+
+		// properties are read-only, and there
+		// are no index expressions to assign to.
+		{"m.x = 1;", true},
+		{"m.x += 1;", true},
+		{"m.x++;", true},
+		{"--m.x;", true},
+	}
+
+	for i, tt := range tests {
+		scanner := scanner.New(tt.input)
+		parser := Parser{
+			Tokens: scanner.ScanTokens(),
+		}
+
+		_, isError := parser.Parse()
+		if isError != tt.expectedError {
+			t.Fatalf("tests[%d] - error wrong for %v. expected=%v", i, tt.input, tt.expectedError)
+		}
+	}
+
+	// invalid targets are reported without
+	// stopping the parsing.
+	scanner := scanner.New("1 = 2; print 3;")
+	parser := Parser{
+		Tokens: scanner.ScanTokens(),
+	}
+
+	statements, _ := parser.Parse()
+	if len(statements) != 2 || statements[0] == nil || statements[1] == nil {
+		t.Fatalf("statements wrong. got=%v", statements)
+	}
+}
+
+// names returns n comma separated names.
+func names(n int) string {
+	var names []string
+	for i := 0; i < n; i++ {
+		names = append(names, fmt.Sprintf("a%v", i))
+	}
+
+	return strings.Join(names, ", ")
+}
+
+func TestLimits(t *testing.T) {
+	tests := []struct {
+		input         string
+		maxParameters int
+		maxArguments  int
+		expectedError bool
+	}{
+		{fmt.Sprintf("fun f(%v) {}", names(255)), 0, 0, false},
+		{fmt.Sprintf("fun f(%v) {}", names(256)), 0, 0, true},
+		{fmt.Sprintf("fun f(%v, ...rest) {}", names(255)), 0, 0, true},
+		{fmt.Sprintf("f(%v);", names(255)), 0, 0, false},
+		{fmt.Sprintf("f(%v);", names(256)), 0, 0, true},
+		{fmt.Sprintf("f(%v, b: 1);", names(255)), 0, 0, true},
+		{fmt.Sprintf("f(%v);", names(300)), 0, 0, true},
+		{"fun f(a, b) {}", 2, 0, false},
+		{"fun f(a, b, c) {}", 2, 0, true},
+		{"f(a, b);", 0, 2, false},
+		{"f(a, b, c);", 0, 2, true},
+		{"f(a, b: 1, c: 2);", 0, 2, true},
+		{"fun f(a, b, c) {}", 0, 2, false},
+		{fmt.Sprintf("fun f(%v) {}", names(255)), -1, -1, false},
+		{fmt.Sprintf("fun f(%v) {}", names(256)), -1, -1, true},
+		{fmt.Sprintf("f(%v);", names(256)), -1, -1, true},
+	}
+
+	for i, tt := range tests {
+		scanner := scanner.New(tt.input)
+		parser := Parser{
+			Tokens:        scanner.ScanTokens(),
+			MaxParameters: tt.maxParameters,
+			MaxArguments:  tt.maxArguments,
+		}
+
+		statements, isError := parser.Parse()
+		if isError != tt.expectedError {
+			t.Fatalf("tests[%d] - error wrong. expected=%v, got=%v", i, tt.expectedError, isError)
+		}
+
+		// the limits are reported without
+		// stopping the parsing.
+		if len(statements) != 1 || statements[0] == nil {
+			t.Fatalf("tests[%d] - statements wrong. got=%v", i, statements)
+		}
+	}
+}
+
+func TestLimitsKeepParsing(t *testing.T) {
+	input := fmt.Sprintf("fun f(%v) { return 1; } print f(%v);", names(256), names(256))
+
+	scanner := scanner.New(input)
+	parser := Parser{
+		Tokens: scanner.ScanTokens(),
+	}
+
+	statements, isError := parser.Parse()
+	if !isError {
+		t.Fatalf("expected an error")
+	}
+
+	if len(statements) != 2 {
+		t.Fatalf("statements wrong. expected=2, got=%v", len(statements))
+	}
+
+	function, ok := statements[0].(*statement.Function)
+	if !ok || len(function.Params) != 256 || len(function.Body) != 1 {
+		t.Fatalf("function wrong. got=%v", statements[0])
+	}
+
+	print, ok := statements[1].(*statement.Print)
+	if !ok || print.Expression == nil {
+		t.Fatalf("print wrong. got=%v", statements[1])
+	}
+}
+
+// parenthesize prints the binary and unary
+// operators of an expression with parentheses.
+func parenthesize(expr ast.Expr) string {
+	switch e := expr.(type) {
+	case *ast.Binary:
+		return fmt.Sprintf("(%v %v %v)", parenthesize(e.Left), e.Operator.Lexeme, parenthesize(e.Right))
+	case *ast.Unary:
+		return fmt.Sprintf("(%v%v)", e.Operator.Lexeme, parenthesize(e.Right))
+	case *ast.Grouping:
+		return parenthesize(e.Expression)
+	case *ast.Literal:
+		return fmt.Sprint(e.Value)
+	}
+
+	return fmt.Sprintf("%T", expr)
+}
+
+func TestPrecedence(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"2 ** 3 ** 2;", "(2 ** (3 ** 2))"},
+		{"-2 ** 2;", "(-(2 ** 2))"},
+		{"2 * 3 ** 2;", "(2 * (3 ** 2))"},
+		{"(2 ** 3) ** 2;", "((2 ** 3) ** 2)"},
+		{"7 ~/ 2 * 3;", "((7 ~/ 2) * 3)"},
+		{"1 + 2 % 3;", "(1 + (2 % 3))"},
+		{"1 | 2 ^ 3 & 4;", "(1 | (2 ^ (3 & 4)))"},
+		{"1 << 2 + 3;", "(1 << (2 + 3))"},
+	}
+
+	for i, tt := range tests {
+		scanner := scanner.New(tt.input)
+		parser := Parser{
+			Tokens: scanner.ScanTokens(),
+		}
+
+		statements, isError := parser.Parse()
+		if isError || len(statements) != 1 {
+			t.Fatalf("tests[%d] - cannot parse %v", i, tt.input)
+		}
+
+		stmt, ok := statements[0].(*statement.Expression)
+		if !ok {
+			t.Fatalf("tests[%d] - statement wrong. expected=*statement.Expression, got=%T", i, statements[0])
+		}
+
+		if got := parenthesize(stmt.Expression); got != tt.expected {
+			t.Fatalf("tests[%d] - expression wrong for %v. expected=%v, got=%v", i, tt.input, tt.expected, got)
+		}
+	}
+}
@@ -0,0 +1,349 @@
+package resolver
+
+import (
+	"fmt"
+	"golox/ast"
+	errorx "golox/error"
+	"golox/statement"
+	"golox/token"
+)
+
+// Resolver statically checks statements before they
+// are interpreted. It reports reassignments and
+// redeclarations of constants that can be detected
+// from the source alone, the interpreter catches the
+// remaining ones at runtime. It also reports names
+// declared with let that are declared again.
+type Resolver struct {
+	// scopes contains the names declared in each
+	// scope, mapped to how they were declared.
+	// The first scope is the global scope.
+	scopes []map[string]binding
+
+	// HadError is set when the resolver
+	// reports an error.
+	HadError bool
+
+	// inFunction is set while resolving
+	// a function body.
+	inFunction bool
+
+	// loops is the number of loops enclosing the
+	// statement being resolved in the current function.
+	loops int
+}
+
+// binding is the kind of declaration of a name.
+type binding int
+
+const (
+	// variable is declared with var, as a function,
+	// a parameter or a loop variable, and can be
+	// redeclared and reassigned.
+	variable binding = iota
+
+	// constant is declared with const, and can
+	// be neither redeclared nor reassigned.
+	constant
+
+	// let is declared with let, and can be
+	// reassigned but not redeclared.
+	let
+)
+
+// error reports an error at the given line
+// and marks the resolver as failed.
+func (r *Resolver) error(line int, message string) {
+	r.HadError = true
+	errorx.Error(line, message)
+}
+
+// beginScope pushes a new scope.
+func (r *Resolver) beginScope() {
+	r.scopes = append(r.scopes, make(map[string]binding))
+}
+
+// endScope pops the innermost scope.
+func (r *Resolver) endScope() {
+	r.scopes = r.scopes[:len(r.scopes)-1]
+}
+
+// declare adds a name to the innermost scope. Constants
+// and names declared with let cannot be redeclared in the
+// same scope, and neither can let redeclare a name.
+func (r *Resolver) declare(name token.Token, kind binding) {
+	scope := r.scopes[len(r.scopes)-1]
+	if declared, ok := scope[name.Lexeme]; ok {
+		switch {
+		case declared == constant:
+			r.error(name.Line, fmt.Sprintf("Cannot redeclare constant %v.", name.Lexeme))
+			return
+		case declared == let || kind == let:
+			r.error(name.Line, fmt.Sprintf("Cannot redeclare variable %v.", name.Lexeme))
+			return
+		}
+	}
+
+	scope[name.Lexeme] = kind
+}
+
+// checkAssignable reports an error if the name
+// resolves to a constant.
+func (r *Resolver) checkAssignable(name token.Token) {
+	for i := len(r.scopes) - 1; i >= 0; i-- {
+		if declared, ok := r.scopes[i][name.Lexeme]; ok {
+			if declared == constant {
+				r.error(name.Line, fmt.Sprintf("Cannot assign to constant %v.", name.Lexeme))
+			}
+
+			return
+		}
+	}
+}
+
+func (r *Resolver) resolveStatements(statements []statement.Stmt) {
+	for _, stmt := range statements {
+		r.resolveStatement(stmt)
+	}
+}
+
+func (r *Resolver) resolveStatement(stmt statement.Stmt) {
+	if stmt != nil {
+		stmt.Accept(r)
+	}
+}
+
+func (r *Resolver) resolveExpression(expr ast.Expr) {
+	if expr != nil {
+		expr.Accept(r)
+	}
+}
+
+// Resolve resolves the statements of a program
+// and returns true if an error was reported.
+func (r *Resolver) Resolve(statements []statement.Stmt) bool {
+	r.beginScope()
+	r.resolveStatements(statements)
+	r.endScope()
+
+	return r.HadError
+}
+
+func (r *Resolver) VisitBlockStmt(stmt *statement.Block) (any, error) {
+	r.beginScope()
+	r.resolveStatements(stmt.Statements)
+	r.endScope()
+	return nil, nil
+}
+
+func (r *Resolver) VisitExpressionStmt(stmt *statement.Expression) (any, error) {
+	r.resolveExpression(stmt.Expression)
+	return nil, nil
+}
+
+func (r *Resolver) VisitFunctionStmt(stmt *statement.Function) (any, error) {
+	r.declare(stmt.Name, variable)
+
+	enclosingFunction := r.inFunction
+	enclosingLoops := r.loops
+	r.inFunction = true
+	r.loops = 0
+
+	r.beginScope()
+	for n, param := range stmt.Params {
+		// default values can refer to the
+		// parameters before them.
+		if n < len(stmt.Defaults) {
+			r.resolveExpression(stmt.Defaults[n])
+		}
+
+		r.declare(param, variable)
+	}
+	r.resolveStatements(stmt.Body)
+	r.endScope()
+
+	r.inFunction = enclosingFunction
+	r.loops = enclosingLoops
+	return nil, nil
+}
+
+// VisitImportStmt declares the imported names, which
+// are constants like in the interpreter.
+func (r *Resolver) VisitImportStmt(stmt *statement.Import) (any, error) {
+	if stmt.Alias.Lexeme != "" {
+		r.declare(stmt.Alias, constant)
+	}
+
+	for _, name := range stmt.Names {
+		r.declare(name, constant)
+	}
+
+	return nil, nil
+}
+
+// VisitExportStmt resolves an exported declaration.
+// Only top-level declarations can be exported.
+func (r *Resolver) VisitExportStmt(stmt *statement.Export) (any, error) {
+	if len(r.scopes) > 1 || r.inFunction {
+		r.error(stmt.Name.Line, "Can only export top-level declarations.")
+	}
+
+	r.resolveStatement(stmt.Declaration)
+	return nil, nil
+}
+
+func (r *Resolver) VisitIfStmt(stmt *statement.If) (any, error) {
+	r.resolveExpression(stmt.Condition)
+	r.resolveStatement(stmt.ThenBranch)
+	r.resolveStatement(stmt.ElseBranch)
+	return nil, nil
+}
+
+func (r *Resolver) VisitPrintStmt(stmt *statement.Print) (any, error) {
+	r.resolveExpression(stmt.Expression)
+	return nil, nil
+}
+
+func (r *Resolver) VisitReturnStmt(stmt *statement.Return) (any, error) {
+	r.resolveExpression(stmt.Value)
+	return nil, nil
+}
+
+func (r *Resolver) VisitYieldStmt(stmt *statement.Yield) (any, error) {
+	if !r.inFunction {
+		r.error(stmt.Keyword.Line, "Can only yield inside a function.")
+	}
+
+	r.resolveExpression(stmt.Value)
+	return nil, nil
+}
+
+func (r *Resolver) VisitVarStmt(stmt *statement.Variable) (any, error) {
+	r.resolveExpression(stmt.Initializer)
+	kind := variable
+	switch {
+	case stmt.Constant:
+		kind = constant
+	case stmt.Let:
+		kind = let
+	}
+
+	r.declare(stmt.Name, kind)
+	return nil, nil
+}
+
+func (r *Resolver) VisitWhileStmt(stmt *statement.While) (any, error) {
+	r.resolveExpression(stmt.Condition)
+
+	r.loops++
+	r.resolveStatement(stmt.Body)
+	r.loops--
+
+	r.resolveExpression(stmt.Increment)
+	return nil, nil
+}
+
+func (r *Resolver) VisitForInStmt(stmt *statement.ForIn) (any, error) {
+	r.resolveExpression(stmt.Iterable)
+
+	r.beginScope()
+	r.declare(stmt.Name, variable)
+
+	r.loops++
+	r.resolveStatement(stmt.Body)
+	r.loops--
+
+	r.endScope()
+	return nil, nil
+}
+
+func (r *Resolver) VisitBreakStmt(stmt *statement.Break) (any, error) {
+	if r.loops == 0 {
+		r.error(stmt.Keyword.Line, "Can only break inside a loop.")
+	}
+
+	return nil, nil
+}
+
+func (r *Resolver) VisitContinueStmt(stmt *statement.Continue) (any, error) {
+	if r.loops == 0 {
+		r.error(stmt.Keyword.Line, "Can only continue inside a loop.")
+	}
+
+	return nil, nil
+}
+
+func (r *Resolver) VisitAssignExpr(expr *ast.Assign) (any, error) {
+	r.resolveExpression(expr.Value)
+	r.checkAssignable(expr.Name)
+	return nil, nil
+}
+
+func (r *Resolver) VisitBinaryExpr(expr *ast.Binary) (any, error) {
+	r.resolveExpression(expr.Left)
+	r.resolveExpression(expr.Right)
+	return nil, nil
+}
+
+func (r *Resolver) VisitCallExpr(expr *ast.Call) (any, error) {
+	r.resolveExpression(expr.Callee)
+	for _, argument := range expr.Arguments {
+		r.resolveExpression(argument)
+	}
+	for _, argument := range expr.Named {
+		r.resolveExpression(argument.Value)
+	}
+	return nil, nil
+}
+
+func (r *Resolver) VisitCoalesceExpr(expr *ast.Coalesce) (any, error) {
+	r.resolveExpression(expr.Left)
+	r.resolveExpression(expr.Right)
+	return nil, nil
+}
+
+func (r *Resolver) VisitConditionalExpr(expr *ast.Conditional) (any, error) {
+	r.resolveExpression(expr.Condition)
+	r.resolveExpression(expr.ThenBranch)
+	r.resolveExpression(expr.ElseBranch)
+	return nil, nil
+}
+
+func (r *Resolver) VisitGetExpr(expr *ast.Get) (any, error) {
+	r.resolveExpression(expr.Object)
+	return nil, nil
+}
+
+func (r *Resolver) VisitGroupingExpr(expr *ast.Grouping) (any, error) {
+	r.resolveExpression(expr.Expression)
+	return nil, nil
+}
+
+func (r *Resolver) VisitLiteralExpr(expr *ast.Literal) (any, error) {
+	return nil, nil
+}
+
+func (r *Resolver) VisitLogicalExpr(expr *ast.Logical) (any, error) {
+	r.resolveExpression(expr.Left)
+	r.resolveExpression(expr.Right)
+	return nil, nil
+}
+
+func (r *Resolver) VisitSpawnExpr(expr *ast.Spawn) (any, error) {
+	r.resolveExpression(expr.Call)
+	return nil, nil
+}
+
+func (r *Resolver) VisitPostfixExpr(expr *ast.Postfix) (any, error) {
+	r.checkAssignable(expr.Name)
+	return nil, nil
+}
+
+func (r *Resolver) VisitUnaryExpr(expr *ast.Unary) (any, error) {
+	r.resolveExpression(expr.Right)
+	return nil, nil
+}
+
+func (r *Resolver) VisitVariableExpr(expr *ast.Variable) (any, error) {
+	return nil, nil
+}
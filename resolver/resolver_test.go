@@ -0,0 +1,156 @@
+package resolver
+
+import (
+	"golox/parser"
+	"golox/scanner"
+	"testing"
+)
+
+func TestConstant(t *testing.T) {
+	tests := []struct {
+		input         string
+		expectedError bool
+	}{
+		{"const a = 1; print a;", false},
+		{"const a = 1; a = 2;", true},
+		{"const a = 1; a += 2;", true},
+		{"const a = 1; { a++; }", true},
+		{"const a = 1; var a = 2;", true},
+		{"const a = 1; { var a = 2; a = 3; }", false},
+		{"const a = 1; fun f() { a = 2; }", true},
+		{"fun f(a) { a = 2; }", false},
+
+		// imported names are constants.
+		{`import { k } from "m"; print k;`, false},
+		{`import { k } from "m"; k = 3;`, true},
+		{`import { k } from "m"; k++;`, true},
+		{`import "m" as m; m = 1;`, true},
+		{`import { k } from "m"; var k = 1;`, true},
+		{`import { k } from "m"; { var k = 1; k = 2; }`, false},
+	}
+
+	for i, tt := range tests {
+		scanner := scanner.New(tt.input)
+		parser := parser.Parser{
+			Tokens: scanner.ScanTokens(),
+		}
+
+		statements, isError := parser.Parse()
+		if isError {
+			t.Fatalf("tests[%d] - unexpected parse error for %v", i, tt.input)
+		}
+
+		resolver := Resolver{}
+		if resolver.Resolve(statements) != tt.expectedError {
+			t.Fatalf("tests[%d] - error wrong for %v. expected=%v", i, tt.input, tt.expectedError)
+		}
+	}
+}
+
+func TestYield(t *testing.T) {
+	tests := []struct {
+		input         string
+		expectedError bool
+	}{
+		{"fun f() { yield 1; }", false},
+		{"fun f() { while (true) { yield; } }", false},
+		{"yield 1;", true},
+		{"{ yield 1; }", true},
+	}
+
+	for i, tt := range tests {
+		scanner := scanner.New(tt.input)
+		parser := parser.Parser{
+			Tokens: scanner.ScanTokens(),
+		}
+
+		statements, isError := parser.Parse()
+		if isError {
+			t.Fatalf("tests[%d] - unexpected parse error for %v", i, tt.input)
+		}
+
+		resolver := Resolver{}
+		if resolver.Resolve(statements) != tt.expectedError {
+			t.Fatalf("tests[%d] - error wrong for %v. expected=%v", i, tt.input, tt.expectedError)
+		}
+	}
+}
+
+func TestJumps(t *testing.T) {
+	tests := []struct {
+		input         string
+		expectedError bool
+	}{
+		{"while (true) break;", false},
+		{"for (;;) { continue; }", false},
+		{"for (x in range(3)) { if (x) break; }", false},
+		{"break;", true},
+		{"{ continue; }", true},
+		{"while (true) { fun f() { break; } }", true},
+	}
+
+	for i, tt := range tests {
+		scanner := scanner.New(tt.input)
+		parser := parser.Parser{
+			Tokens: scanner.ScanTokens(),
+		}
+
+		statements, isError := parser.Parse()
+		if isError {
+			t.Fatalf("tests[%d] - unexpected parse error for %v", i, tt.input)
+		}
+
+		resolver := Resolver{}
+		if resolver.Resolve(statements) != tt.expectedError {
+			t.Fatalf("tests[%d] - error wrong for %v. expected=%v", i, tt.input, tt.expectedError)
+		}
+	}
+}
+
+func TestRedeclaration(t *testing.T) {
+	tests := []struct {
+		input         string
+		expectedError bool
+	}{
+		// var can redeclare variables, as
+		// existing scripts rely on it.
+		{"var x = 1; var x = 2;", false},
+		{"{ var x = 1; var x = 2; }", false},
+		{"var f = 1; fun f() {}", false},
+		{"var x = 1; const x = 2;", false},
+		{"const x = 1; { var x = 2; }", false},
+		{"const x = 1; var x = 2;", true},
+		{"const x = 1; fun x() {}", true},
+
+		// let declares variables that cannot be
+		// redeclared in the same scope.
+		{"let x = 1; x = 2; x++;", false},
+		{"let x = 1; { let x = 2; }", false},
+		{"let x = 1; fun f() { let x = 2; }", false},
+		{"for (let i = 0; i < 1; i++) {} for (let i = 0; i < 1; i++) {}", false},
+		{"let clock = 1;", false},
+		{"let x = 1; let x = 2;", true},
+		{"let x = 1; var x = 2;", true},
+		{"var x = 1; let x = 2;", true},
+		{"let x = 1; fun x() {}", true},
+		{"{ let x = 1; const x = 2; }", true},
+		{"fun f(a) { let a = 1; }", true},
+	}
+
+	for i, tt := range tests {
+		scanner := scanner.New(tt.input)
+		parser := parser.Parser{
+			Tokens: scanner.ScanTokens(),
+		}
+
+		statements, isError := parser.Parse()
+		if isError {
+			t.Fatalf("tests[%d] - unexpected parse error for %v", i, tt.input)
+		}
+
+		resolver := Resolver{}
+		if resolver.Resolve(statements) != tt.expectedError {
+			t.Fatalf("tests[%d] - error wrong for %v. expected=%v", i, tt.input, tt.expectedError)
+		}
+	}
+}
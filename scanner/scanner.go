@@ -1,30 +1,41 @@
 package scanner
 
 import (
+	"fmt"
 	errorx "golox/error"
 	"golox/token"
+	"math/big"
 	"strconv"
+	"strings"
 )
 
 // keywords contain reserved keywords for the
 // golox language.
 var keywords = map[string]token.TokenType{
-	"and":    token.AND,
-	"class":  token.CLASS,
-	"else":   token.ELSE,
-	"false":  token.FALSE,
-	"for":    token.FOR,
-	"fun":    token.FUN,
-	"if":     token.IF,
-	"nil":    token.NIL,
-	"or":     token.OR,
-	"print":  token.PRINT,
-	"return": token.RETURN,
-	"super":  token.SUPER,
-	"this":   token.THIS,
-	"true":   token.TRUE,
-	"var":    token.VAR,
-	"while":  token.WHILE,
+	"and":      token.AND,
+	"break":    token.BREAK,
+	"class":    token.CLASS,
+	"const":    token.CONST,
+	"continue": token.CONTINUE,
+	"else":     token.ELSE,
+	"export":   token.EXPORT,
+	"false":    token.FALSE,
+	"for":      token.FOR,
+	"fun":      token.FUN,
+	"if":       token.IF,
+	"import":   token.IMPORT,
+	"let":      token.LET,
+	"nil":      token.NIL,
+	"or":       token.OR,
+	"print":    token.PRINT,
+	"return":   token.RETURN,
+	"spawn":    token.SPAWN,
+	"super":    token.SUPER,
+	"this":     token.THIS,
+	"true":     token.TRUE,
+	"var":      token.VAR,
+	"while":    token.WHILE,
+	"yield":    token.YIELD,
 }
 
 // Scanner defines a scanner object.
@@ -35,6 +46,10 @@ type Scanner struct {
 
 	Source string
 	Tokens []token.Token
+
+	// HadError is set when the scanner
+	// reports an error.
+	HadError bool
 }
 
 // New creates a new Scanner instance.
@@ -47,6 +62,13 @@ func New(source string) Scanner {
 	}
 }
 
+// error reports an error at the given line
+// and marks the scanner as failed.
+func (s *Scanner) error(line int, message string) {
+	s.HadError = true
+	errorx.Error(line, message)
+}
+
 // isAtEnd checks if the current pointer
 // points at the end of the source.
 func (s *Scanner) isAtEnd() bool {
@@ -141,7 +163,7 @@ func (s *Scanner) string() {
 	}
 
 	if s.isAtEnd() {
-		errorx.Error(s.Line, "Unterminated string.")
+		s.error(s.Line, "Unterminated string.")
 		return
 	}
 
@@ -151,30 +173,205 @@ func (s *Scanner) string() {
 	s.addToken(token.STRING, string(value))
 }
 
+// blockComment skips a block comment. Block comments
+// may be nested, so every "/*" needs a matching "*/".
+func (s *Scanner) blockComment() {
+	startLine := s.Line
+	depth := 1
+
+	for depth > 0 {
+		if s.isAtEnd() {
+			s.error(startLine, "Unterminated block comment.")
+			return
+		}
+
+		if s.peek() == "/" && s.peekNext() == "*" {
+			s.advance()
+			s.advance()
+			depth++
+		} else if s.peek() == "*" && s.peekNext() == "/" {
+			s.advance()
+			s.advance()
+			depth--
+		} else {
+			if s.peek() == "\n" {
+				s.Line++
+			}
+			s.advance()
+		}
+	}
+}
+
+// radixes maps the letter following a leading "0"
+// in a number literal to its base and description.
+var radixes = map[string]struct {
+	base int
+	name string
+}{
+	"x": {16, "hexadecimal literal"},
+	"b": {2, "binary literal"},
+	"o": {8, "octal literal"},
+}
+
+// isDigitOf checks if a single character string
+// is a valid digit in the given base.
+func isDigitOf(base int) func(string) bool {
+	return func(s string) bool {
+		if len(s) > 1 {
+			return false
+		}
+
+		v, err := strconv.ParseUint(s, 36, 8)
+		return err == nil && int(v) < base
+	}
+}
+
+// digits consumes a run of digits accepted by isValid.
+// Digits may be separated by single underscores, which
+// must be surrounded by digits. It reports an error and
+// returns false when a separator is misplaced.
+func (s *Scanner) digits(isValid func(string) bool, kind string) bool {
+	for {
+		for isValid(s.peek()) {
+			s.advance()
+		}
+
+		if s.peek() != "_" {
+			return true
+		}
+
+		s.advance()
+
+		if s.peek() == "_" {
+			s.error(s.Line, fmt.Sprintf("Consecutive '_' in %v.", kind))
+			return false
+		}
+
+		if !isValid(s.peek()) {
+			s.error(s.Line, fmt.Sprintf("Trailing '_' in %v.", kind))
+			return false
+		}
+	}
+}
+
+// skipAlphaNumeric discards the remainder of a
+// malformed literal so it does not produce tokens.
+func (s *Scanner) skipAlphaNumeric() {
+	for isAlphaNumeric(s.peek()) {
+		s.advance()
+	}
+}
+
 // number scans for a number and
-// adds it to the token list.
+// adds it to the token list. Numbers are either
+// decimal with an optional fraction and exponent
+// (123, 1.5, 6.02E23, 1e-9) or integers with a
+// 0x, 0b or 0o prefix. Digits may be grouped
+// with underscores (1_000_000). Literals without
+// a fraction or exponent are integers.
 func (s *Scanner) number() {
-	for isDigit(s.peek()) {
-		s.advance()
+	if radix, ok := radixes[strings.ToLower(s.peek())]; ok && s.Source[s.Start] == '0' {
+		s.radixNumber(radix.base, radix.name)
+		return
+	}
+
+	if !s.digits(isDigit, "number literal") {
+		s.skipAlphaNumeric()
+		return
 	}
 
+	isFloat := false
+
 	if s.peek() == "." && isDigit(s.peekNext()) {
+		isFloat = true
+		s.advance()
+
+		if !s.digits(isDigit, "number literal") {
+			s.skipAlphaNumeric()
+			return
+		}
+	}
 
+	if s.peek() == "e" || s.peek() == "E" {
+		isFloat = true
 		s.advance()
 
-		for isDigit(s.peek()) {
+		if s.peek() == "+" || s.peek() == "-" {
 			s.advance()
 		}
+
+		if !isDigit(s.peek()) {
+			s.error(s.Line, "Expect digits in exponent.")
+			s.skipAlphaNumeric()
+			return
+		}
+
+		if !s.digits(isDigit, "exponent") {
+			s.skipAlphaNumeric()
+			return
+		}
+	}
+
+	if isAlpha(s.peek()) {
+		s.error(s.Line, fmt.Sprintf("Unexpected character '%v' in number literal.", s.peek()))
+		s.skipAlphaNumeric()
+		return
+	}
+
+	text := strings.ReplaceAll(s.Source[s.Start:s.Current], "_", "")
+	if !isFloat {
+		s.addToken(token.NUMBER, parseInteger(text, 10))
+		return
 	}
 
-	num, err := strconv.ParseFloat(string(s.Source[s.Start:s.Current]), 64)
+	num, err := strconv.ParseFloat(text, 64)
 	if err != nil {
-		errorx.Error(s.Line, "Unparsable float")
+		s.error(s.Line, "Number literal out of range.")
+		return
 	}
 
 	s.addToken(token.NUMBER, num)
 }
 
+// radixNumber scans an integer literal written in
+// the given base, starting at its prefix letter.
+func (s *Scanner) radixNumber(base int, kind string) {
+	s.advance()
+
+	isValid := isDigitOf(base)
+	if !isValid(s.peek()) {
+		s.error(s.Line, fmt.Sprintf("Expect digits in %v.", kind))
+		s.skipAlphaNumeric()
+		return
+	}
+
+	if !s.digits(isValid, kind) {
+		s.skipAlphaNumeric()
+		return
+	}
+
+	if isAlphaNumeric(s.peek()) {
+		s.error(s.Line, fmt.Sprintf("Invalid digit '%v' in %v.", s.peek(), kind))
+		s.skipAlphaNumeric()
+		return
+	}
+
+	text := strings.ReplaceAll(s.Source[s.Start+2:s.Current], "_", "")
+	s.addToken(token.NUMBER, parseInteger(text, base))
+}
+
+// parseInteger parses the digits of an integer literal.
+// Integers are int64 unless they are too large, in which
+// case they are represented as *big.Int.
+func parseInteger(digits string, base int) any {
+	if num, err := strconv.ParseInt(digits, base, 64); err == nil {
+		return num
+	}
+
+	num, _ := new(big.Int).SetString(digits, base)
+	return num
+}
+
 // identifier identifies a reserved keyword
 // and adds it to the token list.
 func (s *Scanner) identifier() {
@@ -209,15 +406,64 @@ func (s *Scanner) scanToken() {
 	case ",":
 		s.addToken(token.COMMA, ",")
 	case ".":
-		s.addToken(token.DOT, ".")
+		if s.peek() == "." && s.peekNext() == "." {
+			s.Current += 2
+			s.addToken(token.DOT_DOT_DOT, "...")
+		} else {
+			s.addToken(token.DOT, ".")
+		}
 	case "-":
-		s.addToken(token.MINUS, "-")
+		if s.match("-") {
+			s.addToken(token.MINUS_MINUS, "--")
+		} else if s.match("=") {
+			s.addToken(token.MINUS_EQUAL, "-=")
+		} else {
+			s.addToken(token.MINUS, "-")
+		}
 	case "+":
-		s.addToken(token.PLUS, "+")
+		if s.match("+") {
+			s.addToken(token.PLUS_PLUS, "++")
+		} else if s.match("=") {
+			s.addToken(token.PLUS_EQUAL, "+=")
+		} else {
+			s.addToken(token.PLUS, "+")
+		}
 	case ";":
 		s.addToken(token.SEMICOLON, ";")
 	case "*":
-		s.addToken(token.STAR, "*")
+		if s.match("*") {
+			s.addToken(token.STAR_STAR, "**")
+		} else if s.match("=") {
+			s.addToken(token.STAR_EQUAL, "*=")
+		} else {
+			s.addToken(token.STAR, "*")
+		}
+	case "%":
+		if s.match("=") {
+			s.addToken(token.PERCENT_EQUAL, "%=")
+		} else {
+			s.addToken(token.PERCENT, "%")
+		}
+	case "&":
+		s.addToken(token.AMPERSAND, "&")
+	case "|":
+		s.addToken(token.PIPE, "|")
+	case "^":
+		s.addToken(token.CARET, "^")
+	case "?":
+		if s.match("?") {
+			s.addToken(token.QUESTION_QUESTION, "??")
+		} else {
+			s.addToken(token.QUESTION, "?")
+		}
+	case ":":
+		s.addToken(token.COLON, ":")
+	case "~":
+		if s.match("/") {
+			s.addToken(token.TILDE_SLASH, "~/")
+		} else {
+			s.addToken(token.TILDE, "~")
+		}
 	case "!":
 		if s.match("=") {
 			s.addToken(token.BANG_EQUAL, "!=")
@@ -233,12 +479,16 @@ func (s *Scanner) scanToken() {
 	case "<":
 		if s.match("=") {
 			s.addToken(token.LESS_EQUAL, "<=")
+		} else if s.match("<") {
+			s.addToken(token.LESS_LESS, "<<")
 		} else {
 			s.addToken(token.LESS, "<")
 		}
 	case ">":
 		if s.match("=") {
 			s.addToken(token.GREATER_EQUAL, ">=")
+		} else if s.match(">") {
+			s.addToken(token.GREATER_GREATER, ">>")
 		} else {
 			s.addToken(token.GREATER, ">")
 		}
@@ -247,6 +497,10 @@ func (s *Scanner) scanToken() {
 			for s.peek() != "\n" && !s.isAtEnd() {
 				s.advance()
 			}
+		} else if s.match("*") {
+			s.blockComment()
+		} else if s.match("=") {
+			s.addToken(token.SLASH_EQUAL, "/=")
 		} else {
 			s.addToken(token.SLASH, "/")
 		}
@@ -263,7 +517,7 @@ func (s *Scanner) scanToken() {
 		} else if isAlpha(c) {
 			s.identifier()
 		} else {
-			errorx.Error(s.Line, "Unexpected character "+c)
+			s.error(s.Line, "Unexpected character "+c)
 		}
 	}
 }
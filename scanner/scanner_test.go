@@ -2,6 +2,7 @@ package scanner
 
 import (
 	"golox/token"
+	"math/big"
 	"testing"
 )
 
@@ -49,3 +50,144 @@ func TestToken(t *testing.T) {
 		}
 	}
 }
+
+func TestOperators(t *testing.T) {
+	input := `% ** & | ^ ~ ~/ << >> ++ -- += -= *= /= %= ? : ?? ... .`
+
+	tests := []struct {
+		expectedType    token.TokenType
+		expectedLiteral string
+	}{
+		{token.PERCENT, "%"},
+		{token.STAR_STAR, "**"},
+		{token.AMPERSAND, "&"},
+		{token.PIPE, "|"},
+		{token.CARET, "^"},
+		{token.TILDE, "~"},
+		{token.TILDE_SLASH, "~/"},
+		{token.LESS_LESS, "<<"},
+		{token.GREATER_GREATER, ">>"},
+		{token.PLUS_PLUS, "++"},
+		{token.MINUS_MINUS, "--"},
+		{token.PLUS_EQUAL, "+="},
+		{token.MINUS_EQUAL, "-="},
+		{token.STAR_EQUAL, "*="},
+		{token.SLASH_EQUAL, "/="},
+		{token.PERCENT_EQUAL, "%="},
+		{token.QUESTION, "?"},
+		{token.COLON, ":"},
+		{token.QUESTION_QUESTION, "??"},
+		{token.DOT_DOT_DOT, "..."},
+		{token.DOT, "."},
+	}
+
+	scanner := New(input)
+
+	tokens := scanner.ScanTokens()
+	for i, tt := range tests {
+		if tokens[i].Type != tt.expectedType {
+			t.Fatalf("tests[%d] - tokenType wrong. expected=%v, got=%v", i, tt.expectedType, tokens[i].Type)
+		}
+
+		if tokens[i].Literal != tt.expectedLiteral {
+			t.Fatalf("tests[%d] - literal wrong. expected=%v, got=%v", i, tt.expectedLiteral, tokens[i].Literal)
+		}
+	}
+}
+
+func TestBlockComment(t *testing.T) {
+	input := `a /* one
+	/* nested
+	*/ still comment */ b
+	/**/ c`
+
+	tests := []struct {
+		expectedLiteral string
+		expectedLine    int
+	}{
+		{"a", 1},
+		{"b", 3},
+		{"c", 4},
+	}
+
+	scanner := New(input)
+
+	tokens := scanner.ScanTokens()
+	if len(tokens) != len(tests)+1 {
+		t.Fatalf("token count wrong. expected=%v, got=%v", len(tests)+1, len(tokens))
+	}
+
+	for i, tt := range tests {
+		if tokens[i].Literal != tt.expectedLiteral {
+			t.Fatalf("tests[%d] - literal wrong. expected=%v, got=%v", i, tt.expectedLiteral, tokens[i].Literal)
+		}
+
+		if tokens[i].Line != tt.expectedLine {
+			t.Fatalf("tests[%d] - line wrong. expected=%v, got=%v", i, tt.expectedLine, tokens[i].Line)
+		}
+	}
+}
+
+func TestNumber(t *testing.T) {
+	tests := []struct {
+		input         string
+		expectedValue any
+	}{
+		{"123", int64(123)},
+		{"123.45", 123.45},
+		{"0xFF", int64(255)},
+		{"0b1010", int64(10)},
+		{"0o755", int64(493)},
+		{"1e-9", 1e-9},
+		{"6.02E23", 6.02e23},
+		{"1_000_000", int64(1000000)},
+	}
+
+	for i, tt := range tests {
+		scanner := New(tt.input)
+
+		tokens := scanner.ScanTokens()
+		if scanner.HadError {
+			t.Fatalf("tests[%d] - unexpected error for %v", i, tt.input)
+		}
+
+		if tokens[0].Type != token.NUMBER {
+			t.Fatalf("tests[%d] - tokenType wrong. expected=%v, got=%v", i, token.NUMBER, tokens[0].Type)
+		}
+
+		if tokens[0].Literal != tt.expectedValue {
+			t.Fatalf("tests[%d] - value wrong. expected=%v, got=%v", i, tt.expectedValue, tokens[0].Literal)
+		}
+	}
+}
+
+func TestBigInteger(t *testing.T) {
+	scanner := New("123456789012345678901234567890")
+
+	tokens := scanner.ScanTokens()
+	value, ok := tokens[0].Literal.(*big.Int)
+	if !ok {
+		t.Fatalf("literal type wrong. expected=*big.Int, got=%T", tokens[0].Literal)
+	}
+
+	if value.String() != "123456789012345678901234567890" {
+		t.Fatalf("value wrong. got=%v", value)
+	}
+}
+
+func TestMalformedNumber(t *testing.T) {
+	inputs := []string{"0x", "0b2", "1e", "1e+", "1__0", "1_", "12ab"}
+
+	for i, input := range inputs {
+		scanner := New(input)
+
+		tokens := scanner.ScanTokens()
+		if !scanner.HadError {
+			t.Fatalf("tests[%d] - expected error for %v", i, input)
+		}
+
+		if len(tokens) != 1 {
+			t.Fatalf("tests[%d] - expected only EOF for %v, got=%v", i, input, tokens)
+		}
+	}
+}
@@ -12,26 +12,49 @@ program        → statement* EOF ;
 
 declaration    → funDecl
 			   | varDecl
+			   | constDecl
+			   | importDecl
+			   | exportDecl
 			   | statement ;
 
+importDecl     → "import" STRING "as" IDENTIFIER ";"
+			   | "import" "{" IDENTIFIER ( "," IDENTIFIER )* "}"
+				 "from" STRING ";" ;
+exportDecl     → "export" ( funDecl | varDecl | constDecl ) ;
+
+varDecl        → "var" IDENTIFIER ( "=" expression )? ";" ;
+constDecl      → "const" IDENTIFIER "=" expression ";" ;
+
 funDecl        → "fun" function ;
 function       → IDENTIFIER "(" parameters? ")" block ;
 
-parameters     → IDENTIFIER ( "," IDENTIFIER )* ;
+parameters     → parameter ( "," parameter )* ( "," restParameter )?
+			   | restParameter ;
+parameter      → IDENTIFIER ( "=" expression )? ;
+restParameter  → "..." IDENTIFIER ;
 
 statement      → exprStmt
 			   | forStmt
+			   | forInStmt
 			   | ifStmt
                | printStmt
 			   | returnStmt
+			   | yieldStmt
+			   | breakStmt
+			   | continueStmt
 			   | whileStmt
 			   | block ;
 
 returnStmt      → "return" expression? ";" ;
+yieldStmt       → "yield" expression? ";" ;
+breakStmt       → "break" ";" ;
+continueStmt    → "continue" ";" ;
 
 forStmt        → "for" "(" (varDecl | exprStmt | ";")
 				expression? ";"
 				expression? ")" statement ;
+forInStmt      → "for" "(" "var"? IDENTIFIER "in" expression ")"
+				statement ;
 whileStmt      → "while" "(" expression ")" statement ;
 ifStmt		   → "if" "(" expression ")" statement
 				("else" statement)? ;
@@ -42,14 +65,20 @@ printStmt      → "print" expression ";" ;
 
 type Visitor interface {
 	VisitBlockStmt(stmt *Block) (any, error)
+	VisitBreakStmt(stmt *Break) (any, error)
 	// VisitClassStmt(stmt *Class)
+	VisitContinueStmt(stmt *Continue) (any, error)
+	VisitExportStmt(stmt *Export) (any, error)
 	VisitExpressionStmt(stmt *Expression) (any, error)
+	VisitForInStmt(stmt *ForIn) (any, error)
 	VisitFunctionStmt(stmt *Function) (any, error)
 	VisitIfStmt(stmt *If) (any, error)
+	VisitImportStmt(stmt *Import) (any, error)
 	VisitPrintStmt(stmt *Print) (any, error)
 	VisitReturnStmt(stmt *Return) (any, error)
 	VisitVarStmt(stmt *Variable) (any, error)
 	VisitWhileStmt(stmt *While) (any, error)
+	VisitYieldStmt(stmt *Yield) (any, error)
 }
 
 type Stmt interface {
@@ -74,6 +103,17 @@ type Class struct {
 // 	visitor.VisitClassStmt(c)
 // }
 
+// Export exports the variable declared
+// by a declaration from a module.
+type Export struct {
+	Declaration Stmt
+	Name        token.Token
+}
+
+func (e *Export) Accept(visitor Visitor) (any, error) {
+	return visitor.VisitExportStmt(e)
+}
+
 type Expression struct {
 	Expression ast.Expr
 }
@@ -82,10 +122,53 @@ func (e *Expression) Accept(visitor Visitor) (any, error) {
 	return visitor.VisitExpressionStmt(e)
 }
 
+// ForIn runs its body for each value
+// produced by an iterable.
+type ForIn struct {
+	Name     token.Token
+	Iterable ast.Expr
+	Body     Stmt
+}
+
+func (f *ForIn) Accept(visitor Visitor) (any, error) {
+	return visitor.VisitForInStmt(f)
+}
+
+// Break exits the enclosing loop.
+type Break struct {
+	Keyword token.Token
+}
+
+func (b *Break) Accept(visitor Visitor) (any, error) {
+	return visitor.VisitBreakStmt(b)
+}
+
+// Continue skips to the next iteration
+// of the enclosing loop.
+type Continue struct {
+	Keyword token.Token
+}
+
+func (c *Continue) Accept(visitor Visitor) (any, error) {
+	return visitor.VisitContinueStmt(c)
+}
+
 type Function struct {
 	Name   token.Token
 	Params []token.Token
 	Body   []Stmt
+
+	// Defaults contains the default value of each
+	// parameter, or nil for required parameters.
+	Defaults []ast.Expr
+
+	// Rest is set when the last parameter collects
+	// the remaining arguments in an array.
+	Rest bool
+
+	// Generator is set for functions containing a
+	// yield statement, which return a generator.
+	Generator bool
 }
 
 func (f *Function) Accept(visitor Visitor) (any, error) {
@@ -102,6 +185,19 @@ func (i *If) Accept(visitor Visitor) (any, error) {
 	return visitor.VisitIfStmt(i)
 }
 
+// Import imports a module. The module is either bound
+// to Alias or the variables in Names are imported from it.
+type Import struct {
+	Keyword token.Token
+	Path    token.Token
+	Alias   token.Token
+	Names   []token.Token
+}
+
+func (i *Import) Accept(visitor Visitor) (any, error) {
+	return visitor.VisitImportStmt(i)
+}
+
 type Print struct {
 	Expression ast.Expr
 }
@@ -119,9 +215,27 @@ func (r *Return) Accept(visitor Visitor) (any, error) {
 	return visitor.VisitReturnStmt(r)
 }
 
+// Yield suspends a generator, producing a value.
+type Yield struct {
+	Keyword token.Token
+	Value   ast.Expr
+}
+
+func (y *Yield) Accept(visitor Visitor) (any, error) {
+	return visitor.VisitYieldStmt(y)
+}
+
 type Variable struct {
 	Name        token.Token
 	Initializer ast.Expr
+
+	// Constant is set for variables declared with
+	// "const", which cannot be reassigned.
+	Constant bool
+
+	// Let is set for variables declared with "let",
+	// which cannot be redeclared in the same scope.
+	Let bool
 }
 
 func (v *Variable) Accept(visitor Visitor) (any, error) {
@@ -131,6 +245,11 @@ func (v *Variable) Accept(visitor Visitor) (any, error) {
 type While struct {
 	Condition ast.Expr
 	Body      Stmt
+
+	// Increment is the increment of a for loop,
+	// evaluated after the body even when the body
+	// continues. It is nil for while loops.
+	Increment ast.Expr
 }
 
 func (w *While) Accept(visitor Visitor) (any, error) {
@@ -15,16 +15,39 @@ const (
 	SEMICOLON   = ";"
 	SLASH       = "/"
 	STAR        = "*"
+	PERCENT     = "%"
+	AMPERSAND   = "&"
+	PIPE        = "|"
+	CARET       = "^"
+	TILDE       = "~"
+	QUESTION    = "?"
+	COLON       = ":"
 
 	// At most two character tokens.
-	BANG          = "!"
-	BANG_EQUAL    = "!="
-	EQUAL         = "="
-	EQUAL_EQUAL   = "=="
-	GREATER       = ">"
-	GREATER_EQUAL = ">="
-	LESS          = "<"
-	LESS_EQUAL    = "<="
+	BANG            = "!"
+	BANG_EQUAL      = "!="
+	EQUAL           = "="
+	EQUAL_EQUAL     = "=="
+	GREATER         = ">"
+	GREATER_EQUAL   = ">="
+	GREATER_GREATER = ">>"
+	LESS            = "<"
+	LESS_EQUAL      = "<="
+	LESS_LESS       = "<<"
+	TILDE_SLASH     = "~/"
+	STAR_STAR       = "**"
+	PLUS_PLUS       = "++"
+	MINUS_MINUS     = "--"
+	PLUS_EQUAL      = "+="
+	MINUS_EQUAL     = "-="
+	STAR_EQUAL      = "*="
+	SLASH_EQUAL     = "/="
+	PERCENT_EQUAL   = "%="
+
+	QUESTION_QUESTION = "??"
+
+	// Three character tokens.
+	DOT_DOT_DOT = "..."
 
 	// Literals.
 	IDENTIFIER = "IDENTIFIER"
@@ -32,22 +55,30 @@ const (
 	NUMBER     = "NUMBER"
 
 	// Keywords.
-	AND    = "AND"
-	CLASS  = "CLASS"
-	ELSE   = "ELSE"
-	FALSE  = "FALSE"
-	FUN    = "FUN"
-	FOR    = "FOR"
-	IF     = "IF"
-	NIL    = "NIL"
-	OR     = "OR"
-	PRINT  = "PRINT"
-	RETURN = "RETURN"
-	SUPER  = "SUPER"
-	THIS   = "THIS"
-	TRUE   = "TRUE"
-	VAR    = "VAR"
-	WHILE  = "WHILE"
+	AND      = "AND"
+	BREAK    = "BREAK"
+	CLASS    = "CLASS"
+	CONST    = "CONST"
+	CONTINUE = "CONTINUE"
+	ELSE     = "ELSE"
+	FALSE    = "FALSE"
+	FUN      = "FUN"
+	FOR      = "FOR"
+	IF       = "IF"
+	IMPORT   = "IMPORT"
+	LET      = "LET"
+	EXPORT   = "EXPORT"
+	NIL      = "NIL"
+	OR       = "OR"
+	PRINT    = "PRINT"
+	RETURN   = "RETURN"
+	SPAWN    = "SPAWN"
+	SUPER    = "SUPER"
+	THIS     = "THIS"
+	TRUE     = "TRUE"
+	VAR      = "VAR"
+	WHILE    = "WHILE"
+	YIELD    = "YIELD"
 
 	EOF = "EOF"
 )